@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// how many aliases and macros can expand into one another before it's
+// taken to be a loop
+const maxExpansionDepth = 10
+
+// aliases everyone has, which the trainer's own can replace
+var builtinAliases = map[string]string{
+	"m": "map",
+	"e": "explore",
+	"c": "catch",
+	"q": "exit",
+}
+
+var (
+	aliasNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+	macroParam       = regexp.MustCompile(`\$([1-9])`)
+)
+
+// expandCommand turns an alias or macro at the start of words into the
+// commands it stands for, which can be aliases or macros themselves.
+// Anything else is left as it is.
+func expandCommand(config *cmdConfig, words []string) ([][]string, error) {
+	return expandDepth(config, words, 0)
+}
+
+func expandDepth(config *cmdConfig, words []string, depth int) ([][]string, error) {
+	name, args := words[0], words[1:]
+	if _, ok := config.cmdRegistry[name]; ok {
+		return [][]string{words}, nil
+	}
+
+	var commands [][]string
+	if body, ok := config.macros[name]; ok {
+		text, err := substituteParams(name, body, args)
+		if err != nil {
+			return nil, err
+		}
+		if commands, err = cleanInput(text); err != nil {
+			return nil, fmt.Errorf("macro %s: %w", name, err)
+		}
+	} else if expansion, ok := lookupAlias(config, name); ok {
+		var err error
+		if commands, err = cleanInput(expansion); err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		// the words after an alias go after what it expands to
+		if len(commands) > 0 {
+			last := len(commands) - 1
+			commands[last] = append(commands[last], args...)
+		}
+	} else {
+		return [][]string{words}, nil
+	}
+
+	if depth == maxExpansionDepth {
+		return nil, fmt.Errorf("%s keeps expanding into itself", name)
+	}
+	expanded := [][]string{}
+	for _, command := range commands {
+		more, err := expandDepth(config, command, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, more...)
+	}
+	return expanded, nil
+}
+
+func lookupAlias(config *cmdConfig, name string) (string, bool) {
+	if expansion, ok := config.aliases[name]; ok {
+		return expansion, true
+	}
+	expansion, ok := builtinAliases[name]
+	return expansion, ok
+}
+
+// substituteParams replaces $1 through $9 in a macro's body with the
+// arguments it was given, quoted so they stay one word each.
+func substituteParams(name, body string, args []string) (string, error) {
+	needed := macroParams(body)
+	if len(args) != needed {
+		return "", fmt.Errorf("macro %s takes %d arguments, not %d", name, needed, len(args))
+	}
+	return macroParam.ReplaceAllStringFunc(body, func(param string) string {
+		n, _ := strconv.Atoi(param[1:])
+		return quoteWord(args[n-1])
+	}), nil
+}
+
+// macroParams returns how many arguments a macro's body takes, going by
+// the highest $n in it.
+func macroParams(body string) int {
+	needed := 0
+	for _, match := range macroParam.FindAllStringSubmatch(body, -1) {
+		n, _ := strconv.Atoi(match[1])
+		needed = max(needed, n)
+	}
+	return needed
+}
+
+// quoteWord quotes word, if it needs it, so cleanInput reads it back as
+// the same single word.
+func quoteWord(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n;'\"\\=") {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// commandNames returns the names of every command, alias and macro, for
+// completing and suggesting.
+func commandNames(config *cmdConfig) []string {
+	names := slices.Collect(maps.Keys(config.cmdRegistry))
+	names = slices.AppendSeq(names, maps.Keys(builtinAliases))
+	names = slices.AppendSeq(names, maps.Keys(config.aliases))
+	names = slices.AppendSeq(names, maps.Keys(config.macros))
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// checkAliasName returns an error unless name can be used for a new alias
+// or macro.
+func checkAliasName(config *cmdConfig, name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return errors.New("names can only have lowercase letters, numbers, - and _")
+	}
+	if _, ok := config.cmdRegistry[name]; ok {
+		return fmt.Errorf("%s is already a command", name)
+	}
+	return nil
+}
+
+func commandAlias(config *cmdConfig, in cmdInput) error {
+	if in.isSet("remove") {
+		if in.isSet("definition") {
+			return in.usageError("alias --remove doesn't take a definition")
+		}
+		name := in.get("remove")
+		if _, ok := config.aliases[name]; !ok {
+			return fmt.Errorf("there's no alias %s of yours to remove", name)
+		}
+		delete(config.aliases, name)
+		fmt.Println("Removed", name)
+		return config.saveGlobal()
+	}
+
+	if !in.isSet("definition") {
+		listAliases(config)
+		return nil
+	}
+
+	name, expansion, ok := strings.Cut(in.get("definition"), "=")
+	name, expansion = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(expansion)
+	if !ok || expansion == "" {
+		return in.usageError("an alias is defined like name=expansion")
+	}
+	if err := checkAliasName(config, name); err != nil {
+		return err
+	}
+	if _, ok := config.macros[name]; ok {
+		return fmt.Errorf("%s is already a macro", name)
+	}
+	if _, err := cleanInput(expansion); err != nil {
+		return err
+	}
+
+	if config.aliases == nil {
+		config.aliases = map[string]string{}
+	}
+	config.aliases[name] = expansion
+	fmt.Printf("%s now runs %s\n", name, expansion)
+	return config.saveGlobal()
+}
+
+func listAliases(config *cmdConfig) {
+	all := maps.Clone(builtinAliases)
+	maps.Copy(all, config.aliases)
+
+	width := 0
+	for name := range all {
+		width = max(width, len(name))
+	}
+	fmt.Println("Aliases:")
+	for _, name := range slices.Sorted(maps.Keys(all)) {
+		fmt.Printf("  %-*s = %s\n", width, name, all[name])
+	}
+}
+
+func commandMacro(config *cmdConfig, in cmdInput) error {
+	if in.isSet("remove") {
+		if in.isSet("macro_name") {
+			return in.usageError("macro --remove doesn't take a definition")
+		}
+		name := in.get("remove")
+		if _, ok := config.macros[name]; !ok {
+			return fmt.Errorf("there's no macro %s to remove", name)
+		}
+		delete(config.macros, name)
+		fmt.Println("Removed", name)
+		return config.saveGlobal()
+	}
+
+	if !in.isSet("macro_name") {
+		if len(config.macros) == 0 {
+			fmt.Println("You haven't defined any macros")
+			return nil
+		}
+		fmt.Println("Macros:")
+		for _, name := range slices.Sorted(maps.Keys(config.macros)) {
+			fmt.Printf("  %s: %s\n", name, config.macros[name])
+		}
+		return nil
+	}
+
+	name := in.get("macro_name")
+	if !in.isSet("body") {
+		body, ok := config.macros[name]
+		if !ok {
+			return fmt.Errorf("there's no macro %s", name)
+		}
+		fmt.Printf("%s: %s\n", name, body)
+		return nil
+	}
+
+	body := in.get("body")
+	if err := checkAliasName(config, name); err != nil {
+		return err
+	}
+	if _, ok := config.aliases[name]; ok {
+		return fmt.Errorf("%s is already an alias", name)
+	}
+	if _, err := cleanInput(body); err != nil {
+		return err
+	}
+
+	if config.macros == nil {
+		config.macros = map[string]string{}
+	}
+	config.macros[name] = body
+	fmt.Printf("%s now runs %s, taking %d arguments\n", name, body, macroParams(body))
+	return config.saveGlobal()
+}
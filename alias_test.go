@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestExpandCommand(t *testing.T) {
+	config := cmdConfig{
+		cmdRegistry: map[string]cliCommand{
+			"catch":   {name: "catch"},
+			"explore": {name: "explore"},
+			"pokedex": {name: "pokedex"},
+			"travel":  {name: "travel"},
+		},
+		aliases: map[string]string{
+			"ll":   "pokedex --caught",
+			"e":    "explore --verbose",
+			"loop": "loop",
+		},
+		macros: map[string]string{
+			"hunt":   "travel $1; explore",
+			"catch2": "c $1; c $2",
+		},
+	}
+
+	cases := []struct {
+		input    []string
+		expected [][]string
+		err      string
+	}{
+		{input: []string{"catch", "pikachu"}, expected: [][]string{{"catch", "pikachu"}}},
+		{input: []string{"c", "pikachu"}, expected: [][]string{{"catch", "pikachu"}}},
+		{input: []string{"ll", "kanto"}, expected: [][]string{{"pokedex", "--caught", "kanto"}}},
+		{input: []string{"e"}, expected: [][]string{{"explore", "--verbose"}}},
+		{
+			input:    []string{"hunt", "mt. moon"},
+			expected: [][]string{{"travel", "mt. moon"}, {"explore"}},
+		},
+		{
+			input:    []string{"catch2", "pidgey", "rattata"},
+			expected: [][]string{{"catch", "pidgey"}, {"catch", "rattata"}},
+		},
+		{input: []string{"nope"}, expected: [][]string{{"nope"}}},
+		{input: []string{"hunt"}, err: "macro hunt takes 1 arguments, not 0"},
+		{input: []string{"loop"}, err: "loop keeps expanding into itself"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual, err := expandCommand(&config, c.input)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Errorf("expected error %q, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(actual, c.expected, slices.Equal) {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAliasesAreShared(t *testing.T) {
+	config := cmdConfig{
+		dataDir:     t.TempDir(),
+		cmdRegistry: newRegistry(),
+		rng:         rand.New(rand.NewSource(1)),
+	}
+	if err := startSession(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := commandAlias(&config, parseArgs(t, "alias", "ll=pokedex --caught")); err != nil {
+		t.Fatal(err)
+	}
+	if err := commandAlias(&config, parseArgs(t, "alias", "map=mapb")); err == nil {
+		t.Error("expected an alias named after a command to be refused")
+	}
+	if err := commandProfile(&config, parseArgs(t, "profile", "new", "blue")); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := cmdConfig{dataDir: config.dataDir, rng: config.rng}
+	if err := startSession(&restarted); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.trainer.Name != "blue" || restarted.aliases["ll"] != "pokedex --caught" {
+		t.Errorf("restarted as %q with aliases %v", restarted.trainer.Name, restarted.aliases)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// argKind is how the value of an argument or flag is read.
+type argKind int
+
+const (
+	// text is kept as it was typed, like a nickname
+	textArg argKind = iota
+	// names are lowercased, as the PokeAPI and saves use them
+	nameArg
+	// whole numbers
+	intArg
+)
+
+// argSpec declares one of a command's positional arguments.
+type argSpec struct {
+	name     string
+	help     string
+	kind     argKind
+	optional bool
+	def      string
+
+	// rest takes every word left, joined by spaces, so it has to come last
+	rest bool
+
+	// choices, if there are any, are the only values allowed
+	choices []string
+}
+
+// flagSpec declares one of a command's --flags, which is either given or
+// not, or when value names what it takes, is followed by a value.
+type flagSpec struct {
+	name    string
+	help    string
+	value   string
+	kind    argKind
+	def     string
+	choices []string
+}
+
+// flags every command takes, unless it's text only
+var globalFlags = []flagSpec{
+	{name: "output", value: "format", kind: nameArg, choices: outputFormats, help: "write the result as text, json or yaml"},
+}
+
+// cmdInput is what a command was given, parsed by the arguments and flags
+// it declares.
+type cmdInput struct {
+	cmd    cliCommand
+	values map[string]string
+	given  map[string]bool
+}
+
+// get returns the value of an argument or flag, or its default when it
+// wasn't given. Flags without values are "true" when given.
+func (in cmdInput) get(name string) string {
+	return in.values[name]
+}
+
+// getInt returns the value of a number argument or flag, which parsing has
+// already checked.
+func (in cmdInput) getInt(name string) int {
+	n, _ := strconv.Atoi(in.values[name])
+	return n
+}
+
+// isSet reports whether an argument or flag was given.
+func (in cmdInput) isSet(name string) bool {
+	return in.given[name]
+}
+
+// usageError explains what's wrong with how a command was used, followed by
+// how to use it.
+func (in cmdInput) usageError(format string, a ...any) error {
+	return usageError(in.cmd, fmt.Sprintf(format, a...))
+}
+
+func usageError(cmd cliCommand, reason string) error {
+	return fmt.Errorf("%s\nusage: %s", reason, usage(cmd))
+}
+
+// parseInput reads words, the ones after a command's name, by the command's
+// declared arguments and flags. Flags can go anywhere, except after a "--",
+// which makes the words after it arguments even if they start with --.
+func parseInput(cmd cliCommand, words []string) (cmdInput, error) {
+	in := cmdInput{cmd: cmd, values: map[string]string{}, given: map[string]bool{}}
+	for _, spec := range cmd.args {
+		if spec.def != "" {
+			in.values[spec.name] = spec.def
+		}
+	}
+	for _, flag := range cmd.flags {
+		if flag.def != "" {
+			in.values[flag.name] = flag.def
+		}
+	}
+
+	positional := []string{}
+	flagsDone := false
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" && !flagsDone {
+			flagsDone = true
+			continue
+		}
+		if flagsDone || !strings.HasPrefix(word, "--") {
+			positional = append(positional, word)
+			continue
+		}
+
+		flag, ok := findFlag(cmd, strings.ToLower(word[2:]))
+		if !ok {
+			return in, usageError(cmd, fmt.Sprintf("there's no %s flag", word))
+		}
+		value := "true"
+		if flag.value != "" {
+			if i+1 == len(words) {
+				return in, usageError(cmd, fmt.Sprintf("--%s needs a <%s>", flag.name, flag.value))
+			}
+			i++
+			var err error
+			if value, err = parseValue(flag.kind, flag.choices, "--"+flag.name, words[i]); err != nil {
+				return in, usageError(cmd, err.Error())
+			}
+		}
+		in.values[flag.name] = value
+		in.given[flag.name] = true
+	}
+
+	for i, spec := range cmd.args {
+		if i == len(positional) {
+			if !spec.optional {
+				return in, usageError(cmd, fmt.Sprintf("%s needs a <%s>", cmd.name, spec.name))
+			}
+			break
+		}
+		word := positional[i]
+		if spec.rest {
+			word = strings.Join(positional[i:], " ")
+			positional = positional[:i+1]
+		}
+		value, err := parseValue(spec.kind, spec.choices, "<"+spec.name+">", word)
+		if err != nil {
+			return in, usageError(cmd, err.Error())
+		}
+		in.values[spec.name] = value
+		in.given[spec.name] = true
+	}
+	if len(positional) > len(cmd.args) {
+		return in, usageError(cmd, fmt.Sprintf("%s doesn't take %s", cmd.name, positional[len(cmd.args)]))
+	}
+
+	return in, nil
+}
+
+func findFlag(cmd cliCommand, name string) (flagSpec, bool) {
+	for _, flag := range slices.Concat(cmd.flags, cmd.globalFlags()) {
+		if flag.name == name {
+			return flag, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// globalFlags returns the global flags the command takes.
+func (cmd cliCommand) globalFlags() []flagSpec {
+	if cmd.textOnly {
+		return nil
+	}
+	return globalFlags
+}
+
+// parseValue checks a value is of the kind wanted, and one of the choices
+// if there are any, returning it as the command sees it.
+func parseValue(kind argKind, choices []string, name, value string) (string, error) {
+	switch kind {
+	case nameArg:
+		value = strings.ToLower(value)
+	case intArg:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%s must be a whole number", name)
+		}
+	}
+	if len(choices) > 0 && !slices.Contains(choices, value) {
+		return "", fmt.Errorf("%s is one of %s", name, orList(choices))
+	}
+	return value, nil
+}
+
+// usage returns how to use a command, like "catch <pokemon_name> [--form
+// <form_name>]".
+func usage(cmd cliCommand) string {
+	parts := []string{cmd.name}
+	for _, spec := range cmd.args {
+		parts = append(parts, spec.usage())
+	}
+	for _, flag := range cmd.flags {
+		parts = append(parts, "["+flag.usage()+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (spec argSpec) usage() string {
+	name := spec.name
+	if len(spec.choices) > 0 {
+		name = strings.Join(spec.choices, "|")
+	}
+	if spec.rest {
+		name += "..."
+	}
+	if spec.optional {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+func (flag flagSpec) usage() string {
+	if flag.value == "" {
+		return "--" + flag.name
+	}
+	return fmt.Sprintf("--%s <%s>", flag.name, flag.value)
+}
+
+// onlyOneOf returns an error when more than one of the given flags was set.
+func onlyOneOf(in cmdInput, flags ...string) error {
+	set := 0
+	for _, name := range flags {
+		if in.isSet(name) {
+			set++
+		}
+	}
+	if set < 2 {
+		return nil
+	}
+	names := []string{}
+	for _, name := range flags {
+		names = append(names, "--"+name)
+	}
+	return in.usageError("use only one of %s", orList(names))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+)
+
+// parseArgs parses args the way the REPL would for the named command.
+func parseArgs(t *testing.T, name string, args ...string) cmdInput {
+	t.Helper()
+	in, err := parseInput(newRegistry()[name], args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+func TestParseInput(t *testing.T) {
+	cmd := cliCommand{
+		name: "sprite",
+		args: []argSpec{
+			{name: "pokemon_name", kind: nameArg},
+			{name: "caption", optional: true, rest: true},
+		},
+		flags: []flagSpec{
+			{name: "shiny"},
+			{name: "gen", value: "n", kind: intArg, def: "1"},
+		},
+	}
+
+	cases := []struct {
+		input    []string
+		expected map[string]string
+		err      string
+	}{
+		{
+			input:    []string{"Pikachu"},
+			expected: map[string]string{"pokemon_name": "pikachu", "gen": "1"},
+		},
+		{
+			input:    []string{"--shiny", "pikachu", "--gen", "3", "Big", "Mouse"},
+			expected: map[string]string{"pokemon_name": "pikachu", "shiny": "true", "gen": "3", "caption": "Big Mouse"},
+		},
+		{
+			input:    []string{"pikachu", "--", "--shiny"},
+			expected: map[string]string{"pokemon_name": "pikachu", "gen": "1", "caption": "--shiny"},
+		},
+		{
+			input:    []string{"pikachu", "--output", "JSON"},
+			expected: map[string]string{"pokemon_name": "pikachu", "gen": "1", "output": "json"},
+		},
+		{
+			input: []string{"pikachu", "--output", "xml"},
+			err:   "--output is one of text, json or yaml\nusage: sprite <pokemon_name> [caption...] [--shiny] [--gen <n>]",
+		},
+		{
+			input: []string{},
+			err:   "sprite needs a <pokemon_name>\nusage: sprite <pokemon_name> [caption...] [--shiny] [--gen <n>]",
+		},
+		{
+			input: []string{"pikachu", "--back"},
+			err:   "there's no --back flag\nusage: sprite <pokemon_name> [caption...] [--shiny] [--gen <n>]",
+		},
+		{
+			input: []string{"pikachu", "--gen"},
+			err:   "--gen needs a <n>\nusage: sprite <pokemon_name> [caption...] [--shiny] [--gen <n>]",
+		},
+		{
+			input: []string{"pikachu", "--gen", "two"},
+			err:   "--gen must be a whole number\nusage: sprite <pokemon_name> [caption...] [--shiny] [--gen <n>]",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			in, err := parseInput(cmd, c.input)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Errorf("expected error %q, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(in.values, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, in.values)
+			}
+		})
+	}
+}
+
+func TestParseInputChecksChoices(t *testing.T) {
+	cmd := cliCommand{
+		name: "profile",
+		args: []argSpec{{name: "action", kind: nameArg, choices: []string{"list", "new"}}},
+	}
+
+	if in, err := parseInput(cmd, []string{"NEW"}); err != nil || in.get("action") != "new" {
+		t.Errorf("expected NEW to be read as new, got %q (%v)", in.get("action"), err)
+	}
+	if _, err := parseInput(cmd, []string{"rename"}); err == nil {
+		t.Error("expected rename to be refused")
+	}
+	if _, err := parseInput(cmd, []string{"list", "extra"}); err == nil {
+		t.Error("expected the extra argument to be refused")
+	}
+}
+
+func TestTextOnlyCommandsRefuseOutput(t *testing.T) {
+	called := false
+	cmd := cliCommand{
+		name:     "help",
+		textOnly: true,
+		callback: func(config *cmdConfig, in cmdInput) error {
+			called = true
+			return nil
+		},
+	}
+
+	config := cmdConfig{}
+	if err := callCommand(&config, cmd, []string{"--output", "json"}); err == nil {
+		t.Error("expected --output to be refused")
+	}
+	config.output = "yaml"
+	if err := callCommand(&config, cmd, nil); err == nil {
+		t.Error("expected yaml output for the session to be refused")
+	}
+	config.output = "text"
+	if err := callCommand(&config, cmd, nil); err != nil || !called {
+		t.Errorf("expected help to run with text output, got %v", err)
+	}
+}
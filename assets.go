@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/chuckatc/pokedexcli/internal/assets"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+)
+
+// how many files, or Pokemon, to fetch at once
+const downloadWorkers = 8
+
+// defaultAssetDir returns where downloaded sprites and cries live, or "" if
+// there's no cache directory to keep them in.
+func defaultAssetDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "assets")
+}
+
+// assetScope is which Pokemon assets download fetches the files of.
+type assetScope int
+
+const (
+	onePokemon assetScope = iota
+	caughtPokemon
+	allPokemon
+)
+
+func commandAssets(config *cmdConfig, in cmdInput) error {
+	if config.assets == nil {
+		return errors.New("there's nowhere to keep assets on this system")
+	}
+
+	// which Pokemon to download the files of
+	targets := 0
+	for _, name := range []string{"pokemon_name", "caught", "all"} {
+		if in.isSet(name) {
+			targets++
+		}
+	}
+	if in.get("action") != "download" && targets > 0 {
+		return in.usageError("only assets download takes a Pokemon, --caught or --all")
+	}
+
+	switch in.get("action") {
+	case "":
+		fmt.Printf("%d files in %s\n", config.assets.Len(), config.assets.Dir)
+		return nil
+	case "download":
+		if targets != 1 {
+			return in.usageError("assets download needs one of a <pokemon_name>, --caught or --all")
+		}
+		switch {
+		case in.isSet("caught"):
+			return downloadAssets(config, caughtPokemon, "")
+		case in.isSet("all"):
+			return downloadAssets(config, allPokemon, "")
+		default:
+			return downloadAssets(config, onePokemon, in.get("pokemon_name"))
+		}
+	default:
+		bad, err := config.assets.Verify()
+		if err != nil {
+			return err
+		}
+		for _, path := range bad {
+			fmt.Println("  -", path)
+		}
+		if len(bad) > 0 {
+			return fmt.Errorf("%d files are missing or damaged; download them again to fix them", len(bad))
+		}
+		fmt.Printf("All %d files are intact\n", config.assets.Len())
+		return nil
+	}
+}
+
+// downloadAssets fetches the files of the Pokemon in scope, which for
+// onePokemon is the one named.
+func downloadAssets(config *cmdConfig, scope assetScope, name string) error {
+	if config.settings.Offline {
+		return errors.New("you're offline; turn it off with settings offline off")
+	}
+
+	var names []string
+	switch scope {
+	case caughtPokemon:
+		for _, p := range allOwned(config) {
+			if !slices.Contains(names, p.Pokemon) {
+				names = append(names, p.Pokemon)
+			}
+		}
+		if len(names) == 0 {
+			return errors.New("you haven't caught any Pokemon yet")
+		}
+	case allPokemon:
+		list, err := pokeapi.GetPokemonList(config.cache)
+		if err != nil {
+			return err
+		}
+		for _, pokemon := range list.Results {
+			names = append(names, pokemon.Name)
+		}
+	case onePokemon:
+		names = []string{name}
+	}
+
+	fmt.Printf("Looking up %d Pokemon...\n", len(names))
+	pokemon, failed := getAllPokemonData(config, names)
+	for _, name := range failed {
+		fmt.Printf("can't find %s\n", name)
+	}
+	if len(pokemon) == 0 {
+		return errors.New("there's nothing to download")
+	}
+
+	jobs := []assets.Job{}
+	for _, data := range pokemon {
+		jobs = append(jobs, assetJobs(data, config.settings.Version)...)
+	}
+
+	// stopping with Ctrl-C leaves partial files to resume from next time
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	done, skipped, errs := 0, 0, []error{}
+	err := config.assets.Download(ctx, http.DefaultClient, jobs, downloadWorkers, func(r assets.Result) {
+		done++
+		switch {
+		case r.Err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", r.Job.Path, r.Err))
+		case r.Skipped:
+			skipped++
+		}
+		fmt.Printf("\rDownloading %d/%d", done, len(jobs))
+	})
+	fmt.Println()
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return errors.New("download stopped; run it again to pick up where it left off")
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Downloaded %d files to %s (%d already there, %d failed)\n",
+		done-skipped-len(errs), config.assets.Dir, skipped, len(errs))
+	return nil
+}
+
+// getAllPokemonData looks up each Pokemon a few at a time, returning the
+// data found and the names that weren't.
+func getAllPokemonData(config *cmdConfig, names []string) ([]pokeapi.PokemonData, []string) {
+	results := make([]*pokeapi.PokemonData, len(names))
+	sem := make(chan struct{}, downloadWorkers)
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if data, err := pokeapi.GetPokemonData(name, config.cache); err == nil {
+				results[i] = &data
+			}
+		}()
+	}
+	wg.Wait()
+
+	found, failed := []pokeapi.PokemonData{}, []string{}
+	for i, data := range results {
+		if data == nil {
+			failed = append(failed, names[i])
+		} else {
+			found = append(found, *data)
+		}
+	}
+	return found, failed
+}
+
+// assetJobs lists the sprites and cries of a Pokemon, laid out as
+// <pokemon>/sprites/<variant>.png and <pokemon>/cries/<which>.ogg, with
+// sprites from the trainer's version under sprites/<version>/.
+func assetJobs(data pokeapi.PokemonData, version string) []assets.Job {
+	jobs := []assets.Job{}
+	add := func(url string, path ...string) {
+		if url != "" {
+			jobs = append(jobs, assets.Job{URL: url, Path: filepath.Join(path...)})
+		}
+	}
+
+	for _, shiny := range []bool{false, true} {
+		for _, back := range []bool{false, true} {
+			file := spriteVariant(shiny, back) + ".png"
+			defaultURL := data.SpriteURL("", shiny, back)
+			add(defaultURL, data.Name, "sprites", file)
+			if version != "" {
+				if url := data.SpriteURL(version, shiny, back); url != defaultURL {
+					add(url, data.Name, "sprites", version, file)
+				}
+			}
+		}
+	}
+
+	add(data.Cries.Latest, data.Name, "cries", "latest.ogg")
+	add(data.Cries.Legacy, data.Name, "cries", "legacy.ogg")
+
+	return jobs
+}
+
+func spriteVariant(shiny, back bool) string {
+	side, color := "front", "default"
+	if back {
+		side = "back"
+	}
+	if shiny {
+		color = "shiny"
+	}
+	return side + "-" + color
+}
+
+// loadAsset reads a downloaded file, or fetches it unless the trainer is
+// offline.
+func loadAsset(config *cmdConfig, url string) ([]byte, error) {
+	if config.assets != nil {
+		if path, ok := config.assets.Lookup(url); ok {
+			return os.ReadFile(path)
+		}
+	}
+	if config.settings.Offline {
+		return nil, errors.New("that isn't downloaded; download it with assets download while online")
+	}
+	return pokeapi.GetFile(url, config.cache)
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/chuckatc/pokedexcli/internal/battle"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+// divides base experience times level to give the experience for a win
+const experienceDivisor = 7
+
+const maxMoves = 4
+
+// used when a Pokemon hasn't learned any damaging moves by its level
+const fallbackMove = "tackle"
+
+// activeBattle ties a battle to the trainer's Pokemon and the wild one's
+// data, for the rewards once it's won.
+type activeBattle struct {
+	*battle.Battle
+	owned    *OwnedPokemon
+	wildData pokeapi.PokemonData
+}
+
+func commandBattle(config *cmdConfig, in cmdInput) error {
+	if config.battle != nil {
+		return errors.New("you're already in a battle; use fight or run")
+	}
+	if config.wild == nil {
+		return errors.New("there's no wild Pokemon to battle; use wander to find one")
+	}
+
+	if len(config.party) == 0 {
+		return errors.New("you don't have any Pokemon in your party")
+	}
+	owned := config.party[0]
+	if in.isSet("pokemon_id") {
+		i, err := partyIndex(config, in.get("pokemon_id"))
+		if err != nil {
+			return err
+		}
+		owned = config.party[i]
+	}
+
+	ownedData, err := pokeapi.GetPokemonData(owned.Pokemon, config.cache)
+	if err != nil {
+		return err
+	}
+	wildData, err := pokeapi.GetPokemonData(config.wild.name, config.cache)
+	if err != nil {
+		return err
+	}
+
+	player, err := newCombatant(config, ownedData, owned.Level, owned.Stats())
+	if err != nil {
+		return err
+	}
+	if owned.Nickname != "" {
+		player.Name = owned.Nickname
+	}
+	wildStats := battle.CalcStats(baseStats(wildData), battle.Stats{}, battle.Stats{}, config.wild.level, battle.Nature{})
+	wild, err := newCombatant(config, wildData, config.wild.level, wildStats)
+	if err != nil {
+		return err
+	}
+
+	chart, err := loadTypeChart(config, append(slices.Clone(player.Moves), wild.Moves...))
+	if err != nil {
+		return err
+	}
+
+	config.battle = &activeBattle{
+		Battle:   battle.New(player, wild, chart, config.rng),
+		owned:    owned,
+		wildData: wildData,
+	}
+
+	return writeResult(config, battleStart{newBattleStatus(config.battle.Battle)})
+}
+
+// battleStart is the trainer's Pokemon sent out against the wild one.
+type battleStart struct {
+	battleStatus
+}
+
+func (r battleStart) printText(style table.Style) {
+	fmt.Printf("Go, %s! (Lv. %d)\n", r.Player.Name, r.Player.Level)
+	r.battleStatus.printText(style)
+}
+
+// battleStatus is how both sides of a battle are doing, and the moves the
+// trainer's Pokemon can use.
+type battleStatus struct {
+	Wild   combatantStatus `json:"wild"`
+	Player combatantStatus `json:"player"`
+	Moves  []battle.Move   `json:"moves"`
+}
+
+type combatantStatus struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	HP    int    `json:"hp"`
+	MaxHP int    `json:"max_hp"`
+}
+
+func newBattleStatus(b *battle.Battle) battleStatus {
+	status := func(c *battle.Combatant) combatantStatus {
+		return combatantStatus{Name: c.Name, Level: c.Level, HP: c.HP, MaxHP: c.Stats.HP}
+	}
+	return battleStatus{Wild: status(b.Wild), Player: status(b.Player), Moves: b.Player.Moves}
+}
+
+func (r battleStatus) printText(style table.Style) {
+	fmt.Printf("Wild %s (Lv. %d): %d/%d HP\n", r.Wild.Name, r.Wild.Level, r.Wild.HP, r.Wild.MaxHP)
+	fmt.Printf("Your %s (Lv. %d): %d/%d HP\n", r.Player.Name, r.Player.Level, r.Player.HP, r.Player.MaxHP)
+	fmt.Println("Moves:")
+	for i, move := range r.Moves {
+		fmt.Printf("  %d. %s (%s, %s)\n", i+1, move.Name, move.Type, move.DamageClass)
+	}
+}
+
+func commandFight(config *cmdConfig, in cmdInput) error {
+	if config.battle == nil {
+		return errors.New("you aren't in a battle")
+	}
+	b := config.battle
+
+	choice := in.get("move")
+	move, ok := findMove(b.Player.Moves, choice)
+	if !ok {
+		err := fmt.Errorf("%s doesn't know %s", b.Player.Name, choice)
+		return unknownName(err, choice, func() []string {
+			names := []string{}
+			for _, known := range b.Player.Moves {
+				names = append(names, known.Name)
+			}
+			return names
+		})
+	}
+
+	r := fightResult{Turn: b.Turn(move), player: b.Player.Name, wild: b.Wild.Name}
+
+	switch {
+	case b.Wild.Fainted():
+		config.battle = nil
+		config.wild = nil
+		r.Outcome = "won"
+		if err := rewardWin(config, b, &r); err != nil {
+			return err
+		}
+		if err := writeResult(config, r); err != nil {
+			return err
+		}
+		return config.save()
+	case b.Player.Fainted():
+		config.battle = nil
+		config.wild = nil
+		r.Outcome = "fainted"
+	default:
+		status := newBattleStatus(b.Battle)
+		r.Status = &status
+	}
+
+	return writeResult(config, r)
+}
+
+// fightResult is what happened in a turn of a battle, and how the battle
+// stands after it, or how it ended.
+type fightResult struct {
+	Turn []battle.Result `json:"turn"`
+
+	// "won" or "fainted" once the battle's over, with what was gained by
+	// winning
+	Outcome    string `json:"outcome,omitempty"`
+	Experience int    `json:"experience,omitempty"`
+	NewLevel   int    `json:"new_level,omitempty"`
+
+	Status *battleStatus `json:"status,omitempty"`
+
+	player string
+	wild   string
+}
+
+func (r fightResult) printText(style table.Style) {
+	for _, result := range r.Turn {
+		printAttack(result)
+	}
+
+	switch r.Outcome {
+	case "won":
+		fmt.Printf("The wild %s fainted!\n", r.wild)
+		fmt.Printf("%s gained %d experience.\n", r.player, r.Experience)
+		if r.NewLevel > 0 {
+			fmt.Printf("%s grew to level %d!\n", r.player, r.NewLevel)
+		}
+	case "fainted":
+		fmt.Printf("%s fainted! You hurry away from the wild %s.\n", r.player, r.wild)
+	default:
+		r.Status.printText(style)
+	}
+}
+
+// rewardWin gives the trainer's Pokemon experience and effort for winning,
+// recording what it gained in r.
+func rewardWin(config *cmdConfig, b *activeBattle, r *fightResult) error {
+	experience := b.wildData.BaseExperience * b.Wild.Level / experienceDivisor
+	levels, err := gainExperience(config, b.owned, experience)
+	if err != nil {
+		return err
+	}
+	gainEffort(b.owned, b.wildData)
+
+	r.Experience = experience
+	if levels > 0 {
+		r.NewLevel = b.owned.Level
+	}
+	return nil
+}
+
+func commandRun(config *cmdConfig, in cmdInput) error {
+	if config.battle == nil {
+		return errors.New("you aren't in a battle")
+	}
+
+	r := escapeResult{Wild: config.battle.Wild.Name}
+	config.battle = nil
+	config.wild = nil
+
+	return writeResult(config, r)
+}
+
+// escapeResult is the wild Pokemon the trainer ran from.
+type escapeResult struct {
+	Wild string `json:"wild"`
+}
+
+func (r escapeResult) printText(style table.Style) {
+	fmt.Println("Got away safely!")
+}
+
+func findMove(moves []battle.Move, choice string) (battle.Move, bool) {
+	if n, err := strconv.Atoi(choice); err == nil {
+		if n < 1 || n > len(moves) {
+			return battle.Move{}, false
+		}
+		return moves[n-1], true
+	}
+
+	for _, move := range moves {
+		if move.Name == choice {
+			return move, true
+		}
+	}
+	return battle.Move{}, false
+}
+
+func printAttack(result battle.Result) {
+	fmt.Printf("%s used %s!\n", result.Attacker, result.Move)
+
+	switch {
+	case result.Missed:
+		fmt.Println("  It missed!")
+		return
+	case result.Effectiveness == 0:
+		fmt.Printf("  It doesn't affect %s...\n", result.Defender)
+		return
+	case result.Damage == 0:
+		fmt.Println("  Nothing happened.")
+		return
+	}
+
+	if result.Critical {
+		fmt.Println("  A critical hit!")
+	}
+	if result.Effectiveness > 1 {
+		fmt.Println("  It's super effective!")
+	} else if result.Effectiveness < 1 {
+		fmt.Println("  It's not very effective...")
+	}
+	fmt.Printf("  %s took %d damage.\n", result.Defender, result.Damage)
+}
+
+func newCombatant(config *cmdConfig, pokemon pokeapi.PokemonData, level int, stats battle.Stats) (*battle.Combatant, error) {
+	moves, err := learnedMoves(config, pokemon, level)
+	if err != nil {
+		return nil, err
+	}
+
+	combatant := battle.Combatant{
+		Name:  pokemon.Name,
+		Level: level,
+		Stats: stats,
+		HP:    stats.HP,
+		Moves: moves,
+	}
+	for _, pokeType := range pokemon.Types {
+		combatant.Types = append(combatant.Types, pokeType.Type.Name)
+	}
+
+	return &combatant, nil
+}
+
+func baseStats(pokemon pokeapi.PokemonData) battle.Stats {
+	var stats battle.Stats
+	for _, stat := range pokemon.Stats {
+		if field := statField(&stats, stat.Stat.Name); field != nil {
+			*field = stat.BaseStat
+		}
+	}
+	return stats
+}
+
+// learnedMoves returns the last few moves the Pokemon learned by leveling up
+// to level, the way the games fill in a wild Pokemon's moves.
+func learnedMoves(config *cmdConfig, pokemon pokeapi.PokemonData, level int) ([]battle.Move, error) {
+	type learned struct {
+		name  string
+		level int
+	}
+	learnset := []learned{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name != "level-up" || detail.LevelLearnedAt > level {
+				continue
+			}
+			if config.settings.VersionGroup != "" && detail.VersionGroup.Name != config.settings.VersionGroup {
+				continue
+			}
+			learnset = append(learnset, learned{move.Move.Name, detail.LevelLearnedAt})
+			break
+		}
+	}
+	slices.SortStableFunc(learnset, func(a, b learned) int {
+		return cmp.Compare(b.level, a.level)
+	})
+
+	moves := []battle.Move{}
+	for _, l := range learnset {
+		if len(moves) == maxMoves {
+			break
+		}
+		move, err := getBattleMove(config, l.name)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+
+	if !slices.ContainsFunc(moves, func(move battle.Move) bool { return move.Power > 0 }) {
+		move, err := getBattleMove(config, fallbackMove)
+		if err != nil {
+			return nil, err
+		}
+		if len(moves) == maxMoves {
+			moves = moves[:maxMoves-1]
+		}
+		moves = append(moves, move)
+	}
+
+	return moves, nil
+}
+
+func getBattleMove(config *cmdConfig, name string) (battle.Move, error) {
+	moveData, err := pokeapi.GetMove(name, config.cache)
+	if err != nil {
+		return battle.Move{}, err
+	}
+
+	return battle.Move{
+		Name:        moveData.Name,
+		Type:        moveData.Type.Name,
+		DamageClass: moveData.DamageClass.Name,
+		Power:       moveData.Power,
+		Accuracy:    moveData.Accuracy,
+		Priority:    moveData.Priority,
+	}, nil
+}
+
+// loadTypeChart fetches the damage relations for every type of move that
+// can be used in the battle.
+func loadTypeChart(config *cmdConfig, moves []battle.Move) (battle.TypeChart, error) {
+	chart := battle.TypeChart{}
+	for _, move := range moves {
+		if _, ok := chart[move.Type]; ok {
+			continue
+		}
+
+		typeData, err := pokeapi.GetType(move.Type, config.cache)
+		if err != nil {
+			return nil, err
+		}
+
+		multipliers := map[string]float64{}
+		for _, t := range typeData.DamageRelations.DoubleDamageTo {
+			multipliers[t.Name] = 2
+		}
+		for _, t := range typeData.DamageRelations.HalfDamageTo {
+			multipliers[t.Name] = 0.5
+		}
+		for _, t := range typeData.DamageRelations.NoDamageTo {
+			multipliers[t.Name] = 0
+		}
+		chart[move.Type] = multipliers
+	}
+	return chart, nil
+}
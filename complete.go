@@ -0,0 +1,165 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chuckatc/pokedexcli/internal/lineedit"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+)
+
+// completeInput completes command names, then the arguments and flags
+// commands declare, with the command's own completer offering the rest.
+func completeInput(config *cmdConfig) lineedit.Completer {
+	return func(head string) []string {
+		// only the last of the commands on the line matters
+		if i := strings.LastIndexByte(head, ';'); i >= 0 {
+			head = head[i+1:]
+		}
+		words := strings.Fields(head)
+		if len(words) == 0 || (len(words) == 1 && !strings.HasSuffix(head, " ")) {
+			return commandNames(config)
+		}
+
+		command, ok := aliasedCommand(config, strings.ToLower(words[0]))
+		if !ok {
+			return nil
+		}
+
+		// the arguments before the one being completed
+		args := words[1:]
+		if !strings.HasSuffix(head, " ") {
+			args = args[:len(args)-1]
+		}
+		return completeArgs(config, command, args)
+	}
+}
+
+// aliasedCommand returns the command called name, or the one an alias of
+// just a command name stands for.
+func aliasedCommand(config *cmdConfig, name string) (cliCommand, bool) {
+	if command, ok := config.cmdRegistry[name]; ok {
+		return command, true
+	}
+	if _, ok := config.macros[name]; ok {
+		return cliCommand{}, false
+	}
+	expansion, ok := lookupAlias(config, name)
+	if !ok {
+		return cliCommand{}, false
+	}
+	command, ok := config.cmdRegistry[expansion]
+	return command, ok
+}
+
+// completeArgs offers the flags not given yet, and words for the argument
+// after args.
+func completeArgs(config *cmdConfig, command cliCommand, args []string) []string {
+	positional := []string{}
+	given := map[string]bool{}
+	for i := 0; i < len(args); i++ {
+		flag, ok := findFlag(command, strings.TrimPrefix(args[i], "--"))
+		if !strings.HasPrefix(args[i], "--") || !ok {
+			positional = append(positional, args[i])
+			continue
+		}
+		given[flag.name] = true
+		if flag.value != "" {
+			if i+1 == len(args) {
+				// there's nothing to offer for a flag's value
+				return nil
+			}
+			i++
+		}
+	}
+
+	words := []string{}
+	if n := len(positional); n < len(command.args) && !command.args[n].rest {
+		if command.complete != nil {
+			words = append(words, command.complete(config, positional)...)
+		} else {
+			words = append(words, command.args[n].choices...)
+		}
+	}
+	for _, flag := range command.flags {
+		if !given[flag.name] {
+			words = append(words, "--"+flag.name)
+		}
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return words
+}
+
+// completeCatch offers the wild Pokemon, or those found by the last explore,
+// or failing those every species.
+func completeCatch(config *cmdConfig, args []string) []string {
+	if config.wild != nil {
+		return []string{config.wild.name}
+	}
+	if len(config.lastExplored) > 0 {
+		return config.lastExplored
+	}
+	return apiNames(config, "pokemon-species")
+}
+
+func completeHelp(config *cmdConfig, args []string) []string {
+	return slices.Sorted(maps.Keys(config.cmdRegistry))
+}
+
+// completeOwned offers the IDs, names and nicknames of the trainer's
+// Pokemon.
+func completeOwned(config *cmdConfig, args []string) []string {
+	words := []string{}
+	for _, p := range allOwned(config) {
+		words = append(words, strconv.Itoa(p.ID), p.Pokemon)
+		if p.Nickname != "" && !strings.Contains(p.Nickname, " ") {
+			words = append(words, strings.ToLower(p.Nickname))
+		}
+	}
+	return words
+}
+
+// completeOwnedID offers the IDs of every Pokemon the trainer has, for
+// commands that take nothing else.
+func completeOwnedID(config *cmdConfig, args []string) []string {
+	return ownedIDs(allOwned(config))
+}
+
+// completePartyID offers the IDs of the Pokemon in the party.
+func completePartyID(config *cmdConfig, args []string) []string {
+	return ownedIDs(config.party)
+}
+
+// completeBoxID offers the IDs of the Pokemon in the PC.
+func completeBoxID(config *cmdConfig, args []string) []string {
+	owned := []*OwnedPokemon{}
+	for _, box := range config.boxes {
+		owned = append(owned, box...)
+	}
+	return ownedIDs(owned)
+}
+
+func ownedIDs(owned []*OwnedPokemon) []string {
+	words := []string{}
+	for _, p := range owned {
+		words = append(words, strconv.Itoa(p.ID))
+	}
+	return words
+}
+
+// completeExplore offers the areas of the current location, the only ones
+// explore takes.
+func completeExplore(config *cmdConfig, args []string) []string {
+	if config.location == "" {
+		return nil
+	}
+	location, err := pokeapi.GetLocation(config.location, config.cache)
+	if err != nil {
+		return nil
+	}
+	return resourceNames(location.Areas)
+}
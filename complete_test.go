@@ -0,0 +1,77 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chuckatc/pokedexcli/internal/pokecache"
+)
+
+func TestCompleteInput(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/location/canalave-city",
+		[]byte(`{"name":"canalave-city","areas":[{"name":"canalave-city-area"}]}`))
+
+	config := cmdConfig{
+		cmdRegistry: map[string]cliCommand{
+			"catch": {
+				name:     "catch",
+				complete: completeCatch,
+				args:     []argSpec{{name: "pokemon_name"}},
+				flags:    []flagSpec{{name: "form", value: "form_name"}},
+			},
+			"inspect": {
+				name:     "inspect",
+				complete: completeOwned,
+				args:     []argSpec{{name: "pokemon_id"}},
+				flags:    []flagSpec{{name: "sprite"}},
+			},
+			"explore":  {name: "explore", complete: completeExplore, args: []argSpec{{name: "location_area"}}},
+			"deposit":  {name: "deposit", complete: completePartyID, args: []argSpec{{name: "pokemon_id"}}},
+			"withdraw": {name: "withdraw", complete: completeBoxID, args: []argSpec{{name: "pokemon_id"}}},
+			"release":  {name: "release", complete: completeOwnedID, args: []argSpec{{name: "pokemon_id"}}},
+			"map":      {name: "map"},
+			"profile": {
+				name: "profile",
+				args: []argSpec{{name: "action", choices: []string{"list", "new"}}, {name: "trainer_name"}},
+			},
+		},
+		party:        []*OwnedPokemon{{ID: 7, Pokemon: "pikachu", Nickname: "Sparky"}},
+		boxes:        [][]*OwnedPokemon{{{ID: 9, Pokemon: "eevee"}}},
+		lastExplored: []string{"tentacool", "tentacruel"},
+		location:     "canalave-city",
+		cache:        cache,
+	}
+	complete := completeInput(&config)
+
+	cases := []struct {
+		head     string
+		expected []string
+	}{
+		{head: "", expected: []string{"c", "catch", "deposit", "e", "explore", "inspect", "m", "map", "profile", "q", "release", "withdraw"}},
+		{head: "ca", expected: []string{"c", "catch", "deposit", "e", "explore", "inspect", "m", "map", "profile", "q", "release", "withdraw"}},
+		{head: "c tent", expected: []string{"tentacool", "tentacruel", "--form"}},
+		{head: "catch tent", expected: []string{"tentacool", "tentacruel", "--form"}},
+		{head: "catch tentacool ", expected: []string{"--form"}},
+		{head: "catch tentacool --form ", expected: nil},
+		{head: "catch --form alola ", expected: []string{"tentacool", "tentacruel"}},
+		{head: "inspect 7 --", expected: []string{"--sprite"}},
+		{head: "inspect --sprite ", expected: []string{"7", "pikachu", "sparky", "9", "eevee"}},
+		{head: "deposit ", expected: []string{"7"}},
+		{head: "withdraw ", expected: []string{"9"}},
+		{head: "release ", expected: []string{"7", "9"}},
+		{head: "profile ", expected: []string{"list", "new"}},
+		{head: "profile new ", expected: nil},
+		{head: "explore can", expected: []string{"canalave-city-area"}},
+		{head: "map ", expected: nil},
+		{head: "nope ", expected: nil},
+	}
+
+	for _, c := range cases {
+		actual := complete(c.head)
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("completing %q gave %q; want %q", c.head, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+const defaultEncounterMethod = "walk"
+
+// chance that a wild Pokemon flees after breaking out of a Pokeball
+const fleeProb = 0.3
+
+type wildEncounter struct {
+	name   string
+	url    string
+	level  int
+	method string
+	shiny  bool
+}
+
+type encounterSlot struct {
+	name     string
+	url      string
+	chance   int
+	minLevel int
+	maxLevel int
+}
+
+func commandWander(config *cmdConfig, in cmdInput) error {
+	if config.battle != nil {
+		return errors.New("you're in a battle; use fight or run")
+	}
+	if config.locationArea == "" {
+		return errors.New("you aren't in an area; use travel or area to pick one")
+	}
+
+	exploreData, err := pokeapi.GetExploreData(config.locationArea, config.cache)
+	if err != nil {
+		return err
+	}
+
+	method := defaultEncounterMethod
+	if in.isSet("encounter_method") {
+		method = in.get("encounter_method")
+	} else if !hasEncounterMethod(exploreData, method) {
+		methods := encounterMethods(exploreData)
+		if len(methods) == 0 {
+			return fmt.Errorf("there are no wild Pokemon in %s", config.locationArea)
+		}
+		method = methods[0]
+	}
+
+	version := encounterVersion(config, exploreData)
+	slots := encounterSlots(exploreData, version, method)
+	if len(slots) == 0 {
+		methods := encounterMethods(exploreData)
+		if len(methods) == 0 {
+			return fmt.Errorf("there are no wild Pokemon in %s", config.locationArea)
+		}
+		return fmt.Errorf("nothing turns up by %s in %s; try %s", method, config.locationArea, orList(methods))
+	}
+
+	r := wanderResult{}
+	if config.wild != nil {
+		r.Fled = config.wild.name
+	}
+
+	encounter := sampleEncounter(slots, config.rng.Intn)
+	encounter.method = method
+	encounter.shiny = rollShiny(config)
+	config.wild = &encounter
+	markSeen(config, encounter.name, encounter.url)
+
+	r.Pokemon = encounter.name
+	r.Level = encounter.level
+	r.Method = method
+	r.Shiny = encounter.shiny
+	if err := writeResult(config, r); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// wanderResult is the wild Pokemon that appeared, and the one that fled
+// when it did.
+type wanderResult struct {
+	Pokemon string `json:"pokemon"`
+	Level   int    `json:"level"`
+	Method  string `json:"method"`
+	Shiny   bool   `json:"shiny"`
+	Fled    string `json:"fled,omitempty"`
+}
+
+func (r wanderResult) printText(style table.Style) {
+	if r.Fled != "" {
+		fmt.Printf("The wild %s fled.\n", r.Fled)
+	}
+	if r.Shiny {
+		fmt.Printf("A shiny wild %s (Lv. %d) appeared!\n", r.Pokemon, r.Level)
+	} else {
+		fmt.Printf("A wild %s (Lv. %d) appeared!\n", r.Pokemon, r.Level)
+	}
+}
+
+func rollShiny(config *cmdConfig) bool {
+	return config.rng.Intn(config.settings.shinyOdds()) == 0
+}
+
+// isShinyInArea reports whether a Pokemon found in the current area, other
+// than by wandering, is shiny, rolling for it only the first time.
+func isShinyInArea(config *cmdConfig, name string) bool {
+	if shiny, ok := config.areaShiny[name]; ok {
+		return shiny
+	}
+	if config.areaShiny == nil {
+		config.areaShiny = map[string]bool{}
+	}
+	config.areaShiny[name] = rollShiny(config)
+	return config.areaShiny[name]
+}
+
+// encounterVersion returns the selected game version, or else the first one
+// with encounter data in the area, so that rates from different games aren't
+// mixed together.
+func encounterVersion(config *cmdConfig, exploreData pokeapi.LocationAreaDetailData) string {
+	if config.settings.Version != "" {
+		return config.settings.Version
+	}
+	for _, pokeEncounter := range exploreData.PokemonEncounters {
+		for _, versionDetail := range pokeEncounter.VersionDetails {
+			return versionDetail.Version.Name
+		}
+	}
+	return ""
+}
+
+func encounterMethods(exploreData pokeapi.LocationAreaDetailData) []string {
+	methods := []string{}
+	for _, rate := range exploreData.EncounterMethodRates {
+		methods = append(methods, rate.EncounterMethod.Name)
+	}
+	return methods
+}
+
+func hasEncounterMethod(exploreData pokeapi.LocationAreaDetailData, method string) bool {
+	for _, rate := range exploreData.EncounterMethodRates {
+		if rate.EncounterMethod.Name == method {
+			return true
+		}
+	}
+	return false
+}
+
+func encounterSlots(exploreData pokeapi.LocationAreaDetailData, version, method string) []encounterSlot {
+	slots := []encounterSlot{}
+	for _, pokeEncounter := range exploreData.PokemonEncounters {
+		for _, versionDetail := range pokeEncounter.VersionDetails {
+			if versionDetail.Version.Name != version {
+				continue
+			}
+			for _, detail := range versionDetail.EncounterDetails {
+				if detail.Method.Name != method {
+					continue
+				}
+				slots = append(slots, encounterSlot{
+					name:     pokeEncounter.Pokemon.Name,
+					url:      pokeEncounter.Pokemon.URL,
+					chance:   detail.Chance,
+					minLevel: detail.MinLevel,
+					maxLevel: detail.MaxLevel,
+				})
+			}
+		}
+	}
+	return slots
+}
+
+// catchLevel draws a level for a Pokemon caught in the current area from the
+// ranges it can be encountered at there.
+func catchLevel(config *cmdConfig, name string) (int, bool) {
+	exploreData, err := pokeapi.GetExploreData(config.locationArea, config.cache)
+	if err != nil {
+		return 0, false
+	}
+
+	version := encounterVersion(config, exploreData)
+	slots := []encounterSlot{}
+	for _, method := range encounterMethods(exploreData) {
+		for _, slot := range encounterSlots(exploreData, version, method) {
+			if slot.name == name {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	if len(slots) == 0 {
+		return 0, false
+	}
+
+	return sampleEncounter(slots, config.rng.Intn).level, true
+}
+
+// sampleEncounter picks a slot weighted by its chance and a level within its
+// range. intn is the session's rng.Intn, or a stand-in for it in tests.
+func sampleEncounter(slots []encounterSlot, intn func(int) int) wildEncounter {
+	total := 0
+	for _, slot := range slots {
+		total += slot.chance
+	}
+
+	pick := slots[len(slots)-1]
+	if total > 0 {
+		n := intn(total)
+		for _, slot := range slots {
+			if n < slot.chance {
+				pick = slot
+				break
+			}
+			n -= slot.chance
+		}
+	}
+
+	level := pick.minLevel
+	if pick.maxLevel > pick.minLevel {
+		level += intn(pick.maxLevel - pick.minLevel + 1)
+	}
+
+	return wildEncounter{name: pick.name, url: pick.url, level: level}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSampleEncounter(t *testing.T) {
+	slots := []encounterSlot{
+		{name: "pidgey", chance: 70, minLevel: 2, maxLevel: 4},
+		{name: "rattata", chance: 30, minLevel: 3, maxLevel: 3},
+		{name: "mew", chance: 0, minLevel: 50, maxLevel: 50},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for range 10000 {
+		encounter := sampleEncounter(slots, r.Intn)
+		counts[encounter.name]++
+
+		switch encounter.name {
+		case "pidgey":
+			if encounter.level < 2 || encounter.level > 4 {
+				t.Errorf("pidgey level %d outside 2-4", encounter.level)
+			}
+		case "rattata":
+			if encounter.level != 3 {
+				t.Errorf("rattata level %d; want 3", encounter.level)
+			}
+		}
+	}
+
+	if counts["mew"] != 0 {
+		t.Errorf("sampled a 0%% slot %d times", counts["mew"])
+	}
+	if counts["pidgey"] < 6500 || counts["pidgey"] > 7500 {
+		t.Errorf("pidgey sampled %d times; want about 7000", counts["pidgey"])
+	}
+}
+
+func TestShinyInAreaIsRolledOnce(t *testing.T) {
+	config := cmdConfig{
+		rng:      rand.New(rand.NewSource(1)),
+		settings: settings{ShinyOdds: 2},
+	}
+
+	first := isShinyInArea(&config, "pikachu")
+	for range 20 {
+		if isShinyInArea(&config, "pikachu") != first {
+			t.Fatal("pikachu's shininess changed between catch attempts")
+		}
+	}
+
+	config.moveTo("kanto", "viridian-forest", "viridian-forest-area")
+	if _, ok := config.areaShiny["pikachu"]; ok {
+		t.Error("pikachu's shininess carried over to another area")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// the categories commands are grouped in, in the order help lists them
+var categories = []string{"navigation", "collection", "battle", "data", "system"}
+
+// how wide to wrap long descriptions
+const helpWidth = 72
+
+func commandHelp(config *cmdConfig, in cmdInput) error {
+	if in.isSet("command") {
+		name := in.get("command")
+		command, ok := config.cmdRegistry[name]
+		if !ok {
+			err := fmt.Errorf("there's no %s command", name)
+			return unknownName(err, name, func() []string {
+				return slices.Sorted(maps.Keys(config.cmdRegistry))
+			})
+		}
+		printCommandHelp(command)
+		return nil
+	}
+
+	fmt.Println("Welcome to the Pokedex!")
+	printHelp(config.cmdRegistry)
+	fmt.Println("\nUse help <command> to see how to use one")
+	return nil
+}
+
+// printHelp lists the commands by category, each in alphabetical order.
+func printHelp(registry map[string]cliCommand) {
+	// the descriptions line up in a column after the longest name
+	width := 0
+	for name := range registry {
+		width = max(width, len(name))
+	}
+
+	for _, category := range categories {
+		fmt.Printf("\n%s:\n", strings.ToUpper(category[:1])+category[1:])
+		for _, name := range slices.Sorted(maps.Keys(registry)) {
+			if command := registry[name]; command.category == category {
+				fmt.Printf("  %-*s  %s\n", width, name, command.description)
+			}
+		}
+	}
+}
+
+// printCommandHelp shows how to use a command, and what each of its
+// arguments and flags is for.
+func printCommandHelp(cmd cliCommand) {
+	fmt.Println("usage:", usage(cmd))
+	fmt.Printf("\n%s\n", cmd.description)
+	if cmd.long != "" {
+		fmt.Printf("\n%s\n", wrap(cmd.long, helpWidth))
+	}
+
+	// the help lines up in a column after the longest argument or flag
+	width := 0
+	for _, spec := range cmd.args {
+		width = max(width, len(spec.usage()))
+	}
+	for _, flag := range slices.Concat(cmd.flags, cmd.globalFlags()) {
+		width = max(width, len(flag.usage()))
+	}
+
+	if len(cmd.args) > 0 {
+		fmt.Println("\nArguments:")
+		for _, spec := range cmd.args {
+			help := spec.help
+			if spec.def != "" {
+				help += fmt.Sprintf(" (default %s)", spec.def)
+			}
+			fmt.Printf("  %-*s  %s\n", width, spec.usage(), help)
+		}
+	}
+	if len(cmd.flags) > 0 {
+		fmt.Println("\nFlags:")
+		for _, flag := range cmd.flags {
+			help := flag.help
+			if flag.def != "" {
+				help += fmt.Sprintf(" (default %s)", flag.def)
+			}
+			fmt.Printf("  %-*s  %s\n", width, flag.usage(), help)
+		}
+	}
+
+	if flags := cmd.globalFlags(); len(flags) > 0 {
+		fmt.Println("\nGlobal flags:")
+		for _, flag := range flags {
+			fmt.Printf("  %-*s  %s\n", width, flag.usage(), flag.help)
+		}
+	}
+	if len(cmd.examples) > 0 {
+		fmt.Println("\nExamples:")
+		for _, example := range cmd.examples {
+			fmt.Println("  " + example)
+		}
+	}
+}
+
+// wrap breaks text into lines of at most width characters, between words.
+func wrap(text string, width int) string {
+	var b strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(text) {
+		if lineLen > 0 && lineLen+1+len(word) > width {
+			b.WriteByte('\n')
+			lineLen = 0
+		} else if lineLen > 0 {
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += len(word)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestRegistryIsComplete(t *testing.T) {
+	for name, command := range newRegistry() {
+		if command.name != name {
+			t.Errorf("%s is registered as %s", command.name, name)
+		}
+		if !slices.Contains(categories, command.category) {
+			t.Errorf("%s has no category help lists", name)
+		}
+		if len(command.examples) == 0 {
+			t.Errorf("%s has no examples", name)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		text     string
+		width    int
+		expected string
+	}{
+		{text: "", width: 10, expected: ""},
+		{text: "one two three", width: 10, expected: "one two\nthree"},
+		{text: "one  two\nthree", width: 20, expected: "one two three"},
+		{text: "unbreakable words", width: 5, expected: "unbreakable\nwords"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := wrap(c.text, c.width); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chuckatc/pokedexcli/internal/lineedit"
+	"github.com/chuckatc/pokedexcli/internal/term"
+)
+
+// how many lines of history to remember
+const maxHistory = 1000
+
+// lineReader reads what the trainer types, a line at a time.
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// newInput returns a line editor when stdin is a terminal, and otherwise
+// reads plain lines, as from a pipe.
+func newInput(config *cmdConfig) lineReader {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return scannerInput{bufio.NewScanner(os.Stdin)}
+	}
+
+	historyPath := ""
+	if config.dataDir != "" {
+		historyPath = filepath.Join(config.dataDir, "history")
+	}
+	history, err := lineedit.LoadHistory(historyPath, maxHistory)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "can't read history:", err)
+		history, _ = lineedit.LoadHistory("", maxHistory)
+	}
+	config.history = history
+
+	editor := lineedit.New(os.Stdin, os.Stdout, history)
+	editor.Complete = completeInput(config)
+	return terminalInput{fd: fd, editor: editor}
+}
+
+type scannerInput struct {
+	scanner *bufio.Scanner
+}
+
+func (s scannerInput) ReadLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return s.scanner.Text(), nil
+}
+
+// terminalInput edits lines in raw mode, leaving the terminal as it was
+// while commands run.
+type terminalInput struct {
+	fd     int
+	editor *lineedit.Editor
+}
+
+func (t terminalInput) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(t.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(t.fd, state)
+
+	return t.editor.ReadLine(prompt)
+}
+
+func commandHistory(config *cmdConfig, in cmdInput) error {
+	if config.history == nil {
+		return errors.New("there's only history when typing at a terminal")
+	}
+
+	for i, line := range config.history.Entries() {
+		fmt.Printf("%5d  %s\n", i+1, line)
+	}
+	return nil
+}
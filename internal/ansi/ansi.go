@@ -0,0 +1,67 @@
+// Package ansi writes the escape sequences that color text in the
+// terminal, in as many colors as it can show.
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ColorMode int
+
+const (
+	NoColor ColorMode = iota
+	Color256
+	TrueColor
+)
+
+// Reset undoes every color and other attribute set before it.
+const Reset = "\x1b[0m"
+
+// DetectColorMode guesses how many colors the terminal can show from its
+// environment, as read by getenv.
+func DetectColorMode(getenv func(string) string) ColorMode {
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(getenv("TERM"), "256color") {
+		return Color256
+	}
+	return NoColor
+}
+
+// Fg returns the escape sequence setting the foreground color, or "" when
+// there's no color.
+func Fg(r, g, b uint8, mode ColorMode) string {
+	return sgr(38, r, g, b, mode)
+}
+
+// Bg returns the escape sequence setting the background color, or "" when
+// there's no color.
+func Bg(r, g, b uint8, mode ColorMode) string {
+	return sgr(48, r, g, b, mode)
+}
+
+func sgr(code int, r, g, b uint8, mode ColorMode) string {
+	switch mode {
+	case TrueColor:
+		return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", code, r, g, b)
+	case Color256:
+		return fmt.Sprintf("\x1b[%d;5;%dm", code, Xterm256(r, g, b))
+	default:
+		return ""
+	}
+}
+
+// Xterm256 picks the closest color in the 6x6x6 cube of the 256 color
+// palette.
+func Xterm256(r, g, b uint8) int {
+	level := func(v uint8) int {
+		return (int(v)*5 + 127) / 255
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
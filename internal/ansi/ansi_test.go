@@ -0,0 +1,44 @@
+package ansi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDetectColorMode(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected ColorMode
+	}{
+		{env: map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"}, expected: TrueColor},
+		{env: map[string]string{"TERM": "xterm-256color"}, expected: Color256},
+		{env: map[string]string{"TERM": "vt100"}, expected: NoColor},
+		{env: map[string]string{"COLORTERM": "24bit", "NO_COLOR": "1"}, expected: NoColor},
+	}
+
+	for _, c := range cases {
+		actual := DetectColorMode(func(key string) string { return c.env[key] })
+		if actual != c.expected {
+			t.Errorf("mode for %v is %v; want %v", c.env, actual, c.expected)
+		}
+	}
+}
+
+func TestFg(t *testing.T) {
+	cases := []struct {
+		mode     ColorMode
+		expected string
+	}{
+		{mode: NoColor, expected: ""},
+		{mode: TrueColor, expected: "\x1b[38;2;99;144;240m"},
+		{mode: Color256, expected: "\x1b[38;5;111m"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := Fg(99, 144, 240, c.mode); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
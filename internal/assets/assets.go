@@ -0,0 +1,362 @@
+// Package assets keeps a local library of downloaded sprites and cries, so
+// they can be used without fetching them again.
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const manifestName = "manifest.json"
+
+// partial downloads are kept under this suffix until they're complete
+const partSuffix = ".part"
+
+type Asset struct {
+	URL          string    `json:"url"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Job asks for the file at URL to be stored at Path, relative to the
+// library's directory.
+type Job struct {
+	URL  string
+	Path string
+}
+
+// Result reports how a Job went. Skipped jobs were already in the library.
+type Result struct {
+	Job     Job
+	Skipped bool
+	Err     error
+}
+
+type Library struct {
+	Dir string
+
+	mu     sync.Mutex
+	assets map[string]Asset // by path
+}
+
+// Open loads the library in dir, which needn't exist yet.
+func Open(dir string) (*Library, error) {
+	lib := Library{Dir: dir, assets: map[string]Asset{}}
+
+	contents, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &lib, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, &lib.assets); err != nil {
+		return nil, fmt.Errorf("reading asset manifest: %w", err)
+	}
+
+	return &lib, nil
+}
+
+// Lookup returns the local path of the asset downloaded from url.
+func (lib *Library) Lookup(url string) (string, bool) {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	for path, asset := range lib.assets {
+		if asset.URL == url {
+			fullPath := filepath.Join(lib.Dir, path)
+			if _, err := os.Stat(fullPath); err == nil {
+				return fullPath, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (lib *Library) Len() int {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	return len(lib.assets)
+}
+
+// Download fetches the jobs with up to workers at a time, reporting each
+// result to progress as it finishes. Assets already in the library with a
+// matching checksum are skipped, and interrupted downloads pick up where
+// they left off. The manifest is saved once all jobs are done.
+func (lib *Library) Download(ctx context.Context, client *http.Client, jobs []Job, workers int, progress func(Result)) error {
+	queue := make(chan Job)
+	results := make(chan Result)
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				skipped, err := lib.download(ctx, client, job)
+				results <- Result{Job: job, Skipped: skipped, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		for _, job := range jobs {
+			select {
+			case queue <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if progress != nil {
+			progress(result)
+		}
+	}
+
+	if err := lib.saveManifest(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Verify checks every asset against its recorded checksum and returns the
+// paths of those that are missing or don't match.
+func (lib *Library) Verify() ([]string, error) {
+	lib.mu.Lock()
+	assets := make(map[string]Asset, len(lib.assets))
+	for path, asset := range lib.assets {
+		assets[path] = asset
+	}
+	lib.mu.Unlock()
+
+	bad := []string{}
+	for path, asset := range assets {
+		sum, err := checksum(filepath.Join(lib.Dir, path))
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && sum != asset.SHA256) {
+			bad = append(bad, path)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(bad)
+	return bad, nil
+}
+
+func (lib *Library) download(ctx context.Context, client *http.Client, job Job) (bool, error) {
+	fullPath := filepath.Join(lib.Dir, job.Path)
+
+	lib.mu.Lock()
+	asset, ok := lib.assets[job.Path]
+	lib.mu.Unlock()
+	if ok && asset.URL == job.URL {
+		if sum, err := checksum(fullPath); err == nil && sum == asset.SHA256 {
+			return true, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return false, err
+	}
+	partPath := fullPath + partSuffix
+	if err := fetch(ctx, client, job.URL, partPath); err != nil {
+		return false, err
+	}
+
+	sum, err := checksum(partPath)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return false, err
+	}
+	if err := os.Rename(partPath, fullPath); err != nil {
+		return false, err
+	}
+
+	lib.mu.Lock()
+	lib.assets[job.Path] = Asset{
+		URL:          job.URL,
+		Size:         info.Size(),
+		SHA256:       sum,
+		DownloadedAt: time.Now(),
+	}
+	lib.mu.Unlock()
+
+	return false, nil
+}
+
+// fetch downloads url to path, resuming from the end of what's already
+// there when the server supports range requests. The file must come out the
+// size the server says it is; a partial download that can't be resumed is
+// thrown away and fetched again from the start, and one that comes out the
+// wrong size is removed.
+func fetch(ctx context.Context, client *http.Client, url, path string) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	restart := func() error {
+		res.Body.Close()
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		return fetch(ctx, client, url, path)
+	}
+
+	// the size the whole file should be, or -1 if the server doesn't say
+	size := int64(-1)
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case res.StatusCode == http.StatusPartialContent && offset > 0:
+		start, total, ok := parseContentRange(res.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return restart()
+		}
+		size = total
+		flags |= os.O_APPEND
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// what's there may already be the whole file
+		if _, total, ok := parseContentRange(res.Header.Get("Content-Range")); ok && total == offset {
+			return nil
+		}
+		return restart()
+	case res.StatusCode == http.StatusOK:
+		size = res.ContentLength
+		flags |= os.O_TRUNC
+	default:
+		return fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, res.Body); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if size >= 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.Size() != size {
+			os.Remove(path)
+			return fmt.Errorf("%s: got %d bytes; want %d", url, info.Size(), size)
+		}
+	}
+	return nil
+}
+
+// parseContentRange reads the first byte and the whole file's size from a
+// Content-Range header like "bytes 400-999/1000" or "bytes */1000". The
+// size is -1 when the server doesn't know it, and the first byte is -1 for
+// an unsatisfied range.
+func parseContentRange(header string) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	byteRange, total, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	size := int64(-1)
+	if total != "*" {
+		n, err := strconv.ParseInt(total, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = n
+	}
+	if byteRange == "*" {
+		return -1, size, true
+	}
+
+	first, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+func checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (lib *Library) saveManifest() error {
+	lib.mu.Lock()
+	contents, err := json.MarshalIndent(lib.assets, "", "  ")
+	lib.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(lib.Dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(lib.Dir, manifestName)
+	if err := os.WriteFile(path+".tmp", contents, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,303 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fileServer serves files by path, with range support, counting the
+// requests for each.
+type fileServer struct {
+	files map[string][]byte
+
+	mu       sync.Mutex
+	requests map[string][]string // the Range header of each request
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.URL.Path] = append(s.requests[r.URL.Path], r.Header.Get("Range"))
+	s.mu.Unlock()
+
+	content, ok := s.files[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(content))
+}
+
+func newFileServer(t *testing.T, files map[string][]byte) (*fileServer, *httptest.Server) {
+	fs := &fileServer{files: files, requests: map[string][]string{}}
+	server := httptest.NewServer(fs)
+	t.Cleanup(server.Close)
+	return fs, server
+}
+
+func TestDownload(t *testing.T) {
+	files := map[string][]byte{
+		"/front.png":  []byte("front sprite"),
+		"/back.png":   []byte("back sprite"),
+		"/latest.ogg": bytes.Repeat([]byte("cry"), 1000),
+	}
+	fs, server := newFileServer(t, files)
+
+	dir := t.TempDir()
+	lib, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := []Job{
+		{URL: server.URL + "/front.png", Path: "pikachu/sprites/front.png"},
+		{URL: server.URL + "/back.png", Path: "pikachu/sprites/back.png"},
+		{URL: server.URL + "/latest.ogg", Path: "pikachu/cries/latest.ogg"},
+		{URL: server.URL + "/missing.ogg", Path: "pikachu/cries/legacy.ogg"},
+	}
+
+	var results []Result
+	err = lib.Download(context.Background(), server.Client(), jobs, 2, func(r Result) {
+		results = append(results, r)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results; want %d", len(results), len(jobs))
+	}
+	for _, r := range results {
+		failed := strings.HasSuffix(r.Job.URL, "/missing.ogg")
+		if (r.Err != nil) != failed {
+			t.Errorf("%s: err = %v", r.Job.Path, r.Err)
+		}
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "pikachu", "cries", "latest.ogg"))
+	if err != nil || !bytes.Equal(contents, files["/latest.ogg"]) {
+		t.Errorf("latest.ogg holds %q, %v", contents, err)
+	}
+
+	// the manifest is reloaded, and lookups find the downloaded files
+	lib, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.Len() != 3 {
+		t.Errorf("manifest has %d assets; want 3", lib.Len())
+	}
+	if path, ok := lib.Lookup(server.URL + "/front.png"); !ok || filepath.Base(path) != "front.png" {
+		t.Errorf("looked up %q, %v", path, ok)
+	}
+	if _, ok := lib.Lookup(server.URL + "/missing.ogg"); ok {
+		t.Error("looked up an asset that failed to download")
+	}
+
+	// downloading again skips what's already there
+	err = lib.Download(context.Background(), server.Client(), jobs[:3], 2, func(r Result) {
+		if !r.Skipped {
+			t.Errorf("%s was downloaded again", r.Job.Path)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fs.requests["/front.png"]); n != 1 {
+		t.Errorf("front.png was requested %d times; want 1", n)
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	fs, server := newFileServer(t, map[string][]byte{"/cry.ogg": content})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cry.ogg")
+	if err := os.WriteFile(path+partSuffix, content[:400], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lib, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := []Job{{URL: server.URL + "/cry.ogg", Path: "cry.ogg"}}
+	if err := lib.Download(context.Background(), server.Client(), jobs, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if ranges := fs.requests["/cry.ogg"]; len(ranges) != 1 || ranges[0] != "bytes=400-" {
+		t.Errorf("requested ranges %q; want [bytes=400-]", ranges)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(contents, content) {
+		t.Errorf("resumed download holds %d bytes, %v", len(contents), err)
+	}
+	if _, err := os.Stat(path + partSuffix); err == nil {
+		t.Error("the partial download was left behind")
+	}
+}
+
+func TestDownloadRestarts(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	cases := []struct {
+		name    string
+		part    []byte
+		handler http.HandlerFunc
+		ranges  []string
+	}{
+		{
+			name: "stale partial download",
+			part: bytes.Repeat([]byte("x"), 1200),
+			// ServeContent answers 416 for a range past the end
+			ranges: []string{"bytes=1200-", ""},
+		},
+		{
+			name: "range ignored",
+			part: content[:400],
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "" {
+					w.Header().Set("Content-Range", "bytes 0-999/1000")
+					w.WriteHeader(http.StatusPartialContent)
+				}
+				w.Write(content)
+			},
+			ranges: []string{"bytes=400-", ""},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fs, server := newFileServer(t, map[string][]byte{"/cry.ogg": content})
+			if c.handler != nil {
+				// record the ranges asked for, but answer with the handler
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					fs.mu.Lock()
+					fs.requests[r.URL.Path] = append(fs.requests[r.URL.Path], r.Header.Get("Range"))
+					fs.mu.Unlock()
+					c.handler(w, r)
+				}))
+				t.Cleanup(server.Close)
+			}
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "cry.ogg")
+			if err := os.WriteFile(path+partSuffix, c.part, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			lib, err := Open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			jobs := []Job{{URL: server.URL + "/cry.ogg", Path: "cry.ogg"}}
+			err = lib.Download(context.Background(), server.Client(), jobs, 1, func(r Result) {
+				if r.Err != nil {
+					t.Error(r.Err)
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ranges := fs.requests["/cry.ogg"]; !slices.Equal(ranges, c.ranges) {
+				t.Errorf("requested ranges %q; want %q", ranges, c.ranges)
+			}
+			contents, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(contents, content) {
+				t.Errorf("download holds %d bytes, %v", len(contents), err)
+			}
+		})
+	}
+}
+
+func TestDownloadRejectsShortFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the rest of the file, from a server that's wrong about its size
+		w.Header().Set("Content-Range", "bytes 400-999/2000")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[400:])
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cry.ogg")
+	if err := os.WriteFile(path+partSuffix, content[:400], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lib, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := []Job{{URL: server.URL + "/cry.ogg", Path: "cry.ogg"}}
+	var result Result
+	err = lib.Download(context.Background(), server.Client(), jobs, 1, func(r Result) { result = r })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Err == nil {
+		t.Error("a download of the wrong size succeeded")
+	}
+	if lib.Len() != 0 {
+		t.Errorf("manifest has %d assets; want 0", lib.Len())
+	}
+	for _, p := range []string{path, path + partSuffix} {
+		if _, err := os.Stat(p); err == nil {
+			t.Errorf("%s was left behind", filepath.Base(p))
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	_, server := newFileServer(t, map[string][]byte{
+		"/a.png": []byte("a"),
+		"/b.png": []byte("b"),
+	})
+
+	dir := t.TempDir()
+	lib, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := []Job{
+		{URL: server.URL + "/a.png", Path: "a.png"},
+		{URL: server.URL + "/b.png", Path: "b.png"},
+	}
+	if err := lib.Download(context.Background(), server.Client(), jobs, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "b.png"), []byte("corrupted"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bad, err := lib.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bad) != 1 || bad[0] != "b.png" {
+		t.Errorf("verify found %q; want [b.png]", bad)
+	}
+
+	// a corrupted asset is downloaded again
+	err = lib.Download(context.Background(), server.Client(), jobs, 2, func(r Result) {
+		if r.Skipped != (r.Job.Path == "a.png") {
+			t.Errorf("%s skipped = %v", r.Job.Path, r.Skipped)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bad, _ := lib.Verify(); len(bad) != 0 {
+		t.Errorf("verify found %q after downloading again", bad)
+	}
+}
@@ -0,0 +1,223 @@
+// Package battle runs turn-based battles between two Pokemon using the
+// mainline games' damage formula.
+package battle
+
+import (
+	"math/rand"
+	"slices"
+)
+
+const (
+	stabMultiplier     = 1.5
+	criticalMultiplier = 1.5
+	criticalOdds       = 24 // a 1 in criticalOdds chance of a critical hit
+)
+
+type Stats struct {
+	HP             int `json:"hp"`
+	Attack         int `json:"attack"`
+	Defense        int `json:"defense"`
+	SpecialAttack  int `json:"special_attack"`
+	SpecialDefense int `json:"special_defense"`
+	Speed          int `json:"speed"`
+}
+
+type Move struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DamageClass string `json:"damage_class"` // physical, special or status
+	Power       int    `json:"power"`        // 0 for moves that don't deal damage directly
+	Accuracy    int    `json:"accuracy"`     // 0 for moves that never miss
+	Priority    int    `json:"priority"`
+}
+
+type Combatant struct {
+	Name  string
+	Level int
+	Types []string
+	Stats Stats
+	HP    int
+	Moves []Move
+}
+
+func (c *Combatant) Fainted() bool {
+	return c.HP <= 0
+}
+
+// TypeChart holds the damage multiplier for an attacking type against a
+// defending type. Pairs that aren't listed deal normal damage.
+type TypeChart map[string]map[string]float64
+
+func (chart TypeChart) Effectiveness(moveType string, defenderTypes []string) float64 {
+	multiplier := 1.0
+	for _, defenderType := range defenderTypes {
+		if m, ok := chart[moveType][defenderType]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Result describes one Pokemon's action during a turn.
+type Result struct {
+	Attacker      string  `json:"attacker"`
+	Defender      string  `json:"defender"`
+	Move          string  `json:"move"`
+	Damage        int     `json:"damage"`
+	Missed        bool    `json:"missed"`
+	Critical      bool    `json:"critical"`
+	Effectiveness float64 `json:"effectiveness"`
+	Fainted       bool    `json:"fainted"`
+}
+
+type Battle struct {
+	Player *Combatant
+	Wild   *Combatant
+	chart  TypeChart
+	rng    *rand.Rand
+}
+
+// New starts a battle. rng decides accuracy, critical hits, damage rolls and
+// the wild Pokemon's moves, so a seeded rng replays the same battle.
+func New(player, wild *Combatant, chart TypeChart, rng *rand.Rand) *Battle {
+	return &Battle{
+		Player: player,
+		Wild:   wild,
+		chart:  chart,
+		rng:    rng,
+	}
+}
+
+func (b *Battle) Over() bool {
+	return b.Player.Fainted() || b.Wild.Fainted()
+}
+
+// Turn plays the player's move against a randomly chosen wild move, in order
+// of priority and then speed, stopping once either side faints.
+func (b *Battle) Turn(playerMove Move) []Result {
+	wildMove := b.Wild.Moves[b.rng.Intn(len(b.Wild.Moves))]
+
+	type action struct {
+		attacker, defender *Combatant
+		move               Move
+	}
+	actions := []action{
+		{b.Player, b.Wild, playerMove},
+		{b.Wild, b.Player, wildMove},
+	}
+	if b.wildGoesFirst(playerMove, wildMove) {
+		slices.Reverse(actions)
+	}
+
+	results := []Result{}
+	for _, a := range actions {
+		if b.Over() {
+			break
+		}
+		results = append(results, b.attack(a.attacker, a.defender, a.move))
+	}
+	return results
+}
+
+func (b *Battle) wildGoesFirst(playerMove, wildMove Move) bool {
+	if playerMove.Priority != wildMove.Priority {
+		return wildMove.Priority > playerMove.Priority
+	}
+	if b.Player.Stats.Speed != b.Wild.Stats.Speed {
+		return b.Wild.Stats.Speed > b.Player.Stats.Speed
+	}
+	return b.rng.Intn(2) == 0
+}
+
+func (b *Battle) attack(attacker, defender *Combatant, move Move) Result {
+	result := Result{
+		Attacker:      attacker.Name,
+		Defender:      defender.Name,
+		Move:          move.Name,
+		Effectiveness: 1,
+	}
+
+	if move.Accuracy > 0 && b.rng.Intn(100) >= move.Accuracy {
+		result.Missed = true
+		return result
+	}
+	if move.Power == 0 || move.DamageClass == "status" {
+		return result
+	}
+
+	result.Critical = b.rng.Intn(criticalOdds) == 0
+	result.Effectiveness = b.chart.Effectiveness(move.Type, defender.Types)
+	random := float64(85+b.rng.Intn(16)) / 100
+
+	result.Damage = Damage(attacker, defender, move, result.Effectiveness, result.Critical, random)
+	defender.HP = max(defender.HP-result.Damage, 0)
+	result.Fainted = defender.Fainted()
+
+	return result
+}
+
+// Damage applies the damage formula from generation V onwards, without the
+// modifiers for weather, abilities, held items and the like.
+func Damage(attacker, defender *Combatant, move Move, effectiveness float64, critical bool, random float64) int {
+	attack, defense := attacker.Stats.Attack, defender.Stats.Defense
+	if move.DamageClass == "special" {
+		attack, defense = attacker.Stats.SpecialAttack, defender.Stats.SpecialDefense
+	}
+	defense = max(defense, 1)
+
+	base := (2*attacker.Level/5+2)*move.Power*attack/defense/50 + 2
+
+	modifier := random * effectiveness
+	if critical {
+		modifier *= criticalMultiplier
+	}
+	if slices.Contains(attacker.Types, move.Type) {
+		modifier *= stabMultiplier
+	}
+
+	damage := int(float64(base) * modifier)
+	if damage == 0 && effectiveness > 0 {
+		damage = 1
+	}
+	return damage
+}
+
+// Nature raises one stat by 10% and lowers another by 10%. Natures that
+// raise and lower the same stat, or name none, are neutral.
+type Nature struct {
+	Name      string `json:"name"`
+	Increased string `json:"increased,omitempty"`
+	Decreased string `json:"decreased,omitempty"`
+}
+
+func (n Nature) apply(stat string, value int) int {
+	if n.Increased == n.Decreased {
+		return value
+	}
+	switch stat {
+	case n.Increased:
+		return value * 110 / 100
+	case n.Decreased:
+		return value * 90 / 100
+	}
+	return value
+}
+
+// CalcStats returns the actual stats of a Pokemon at level, given its
+// species' base stats, its individual and effort values and its nature.
+func CalcStats(base, ivs, evs Stats, level int, nature Nature) Stats {
+	calc := func(baseStat, iv, ev int) int {
+		return (2*baseStat + iv + ev/4) * level / 100
+	}
+	other := func(stat string, baseStat, iv, ev int) int {
+		return nature.apply(stat, calc(baseStat, iv, ev)+5)
+	}
+	return Stats{
+		HP:             calc(base.HP, ivs.HP, evs.HP) + level + 10,
+		Attack:         other("attack", base.Attack, ivs.Attack, evs.Attack),
+		Defense:        other("defense", base.Defense, ivs.Defense, evs.Defense),
+		SpecialAttack:  other("special-attack", base.SpecialAttack, ivs.SpecialAttack, evs.SpecialAttack),
+		SpecialDefense: other("special-defense", base.SpecialDefense, ivs.SpecialDefense, evs.SpecialDefense),
+		Speed:          other("speed", base.Speed, ivs.Speed, evs.Speed),
+	}
+}
@@ -0,0 +1,171 @@
+package battle
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDamage(t *testing.T) {
+	attacker := &Combatant{
+		Level: 50,
+		Types: []string{"normal"},
+		Stats: Stats{Attack: 100, SpecialAttack: 50},
+	}
+	defender := &Combatant{
+		Level: 50,
+		Types: []string{"grass"},
+		Stats: Stats{Defense: 100, SpecialDefense: 100},
+	}
+
+	cases := []struct {
+		move          Move
+		effectiveness float64
+		critical      bool
+		random        float64
+		expected      int
+	}{
+		{
+			move:          Move{Type: "fighting", DamageClass: "physical", Power: 40},
+			effectiveness: 1,
+			random:        1,
+			expected:      19,
+		},
+		{
+			move:          Move{Type: "normal", DamageClass: "physical", Power: 40},
+			effectiveness: 1,
+			random:        1,
+			expected:      28,
+		},
+		{
+			move:          Move{Type: "fire", DamageClass: "physical", Power: 40},
+			effectiveness: 2,
+			critical:      true,
+			random:        0.85,
+			expected:      48,
+		},
+		{
+			move:          Move{Type: "fire", DamageClass: "special", Power: 40},
+			effectiveness: 1,
+			random:        1,
+			expected:      10,
+		},
+		{
+			move:          Move{Type: "ghost", DamageClass: "physical", Power: 40},
+			effectiveness: 0,
+			random:        1,
+			expected:      0,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			damage := Damage(attacker, defender, c.move, c.effectiveness, c.critical, c.random)
+			if damage != c.expected {
+				t.Errorf("damage is %d; want %d", damage, c.expected)
+			}
+		})
+	}
+}
+
+func TestEffectiveness(t *testing.T) {
+	chart := TypeChart{
+		"electric": {"water": 2, "flying": 2, "ground": 0},
+		"water":    {"fire": 2, "water": 0.5},
+	}
+
+	cases := []struct {
+		moveType      string
+		defenderTypes []string
+		expected      float64
+	}{
+		{"electric", []string{"water", "flying"}, 4},
+		{"electric", []string{"water", "ground"}, 0},
+		{"water", []string{"water"}, 0.5},
+		{"normal", []string{"water"}, 1},
+	}
+
+	for _, c := range cases {
+		actual := chart.Effectiveness(c.moveType, c.defenderTypes)
+		if actual != c.expected {
+			t.Errorf("%s against %v is %v; want %v", c.moveType, c.defenderTypes, actual, c.expected)
+		}
+	}
+}
+
+func TestCalcStats(t *testing.T) {
+	cases := []struct {
+		base     Stats
+		ivs      Stats
+		evs      Stats
+		level    int
+		nature   Nature
+		expected Stats
+	}{
+		{
+			base:     Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90},
+			level:    50,
+			expected: Stats{HP: 95, Attack: 60, Defense: 45, SpecialAttack: 55, SpecialDefense: 55, Speed: 95},
+		},
+		{
+			base:     Stats{HP: 108, Attack: 130, Defense: 95, SpecialAttack: 80, SpecialDefense: 85, Speed: 102},
+			ivs:      Stats{HP: 24, Attack: 12, Defense: 30, SpecialAttack: 16, SpecialDefense: 23, Speed: 5},
+			evs:      Stats{HP: 74, Attack: 190, Defense: 91, SpecialAttack: 48, SpecialDefense: 84, Speed: 23},
+			level:    78,
+			nature:   Nature{Name: "adamant", Increased: "attack", Decreased: "special-attack"},
+			expected: Stats{HP: 289, Attack: 278, Defense: 193, SpecialAttack: 135, SpecialDefense: 171, Speed: 171},
+		},
+		{
+			base:     Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90},
+			level:    50,
+			nature:   Nature{Name: "hardy", Increased: "attack", Decreased: "attack"},
+			expected: Stats{HP: 95, Attack: 60, Defense: 45, SpecialAttack: 55, SpecialDefense: 55, Speed: 95},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual := CalcStats(c.base, c.ivs, c.evs, c.level, c.nature)
+			if actual != c.expected {
+				t.Errorf("stats are %+v; want %+v", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestSeededBattleIsDeterministic(t *testing.T) {
+	play := func(seed int64) [][]Result {
+		newCombatant := func(name string) *Combatant {
+			base := Stats{HP: 45, Attack: 49, Defense: 49, SpecialAttack: 65, SpecialDefense: 65, Speed: 45}
+			stats := CalcStats(base, Stats{}, Stats{}, 10, Nature{})
+			return &Combatant{
+				Name:  name,
+				Level: 10,
+				Types: []string{"grass"},
+				Stats: stats,
+				HP:    stats.HP,
+				Moves: []Move{
+					{Name: "tackle", Type: "normal", DamageClass: "physical", Power: 40, Accuracy: 100},
+					{Name: "vine-whip", Type: "grass", DamageClass: "physical", Power: 45, Accuracy: 100},
+				},
+			}
+		}
+		player, wild := newCombatant("bulbasaur"), newCombatant("oddish")
+		b := New(player, wild, TypeChart{"grass": {"grass": 0.5}}, rand.New(rand.NewSource(seed)))
+
+		turns := [][]Result{}
+		for !b.Over() {
+			turns = append(turns, b.Turn(player.Moves[0]))
+		}
+		return turns
+	}
+
+	first, second := play(42), play(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("battles with the same seed differ:\n%+v\n%+v", first, second)
+	}
+	if len(first) == 0 {
+		t.Errorf("expected the battle to take at least one turn")
+	}
+}
@@ -0,0 +1,78 @@
+// Package fuzzy finds the words closest to a misspelled one.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+)
+
+// Distance returns how many edits turn a into b, where an edit inserts,
+// deletes or substitutes a rune, or swaps two adjacent ones. (This is the
+// optimal string alignment form of Damerau-Levenshtein distance, which
+// doesn't edit a substring twice.)
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// rows i-2, i-1 and i of the edit table
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(t)]
+}
+
+// MaxDistance is how far a word can be from what was meant and still count
+// as a misspelling of it: a third of its length, but at least one edit.
+func MaxDistance(word string) int {
+	return max(len([]rune(word))/3, 1)
+}
+
+// Closest returns up to n of the candidates within MaxDistance of word,
+// nearest first. Case is ignored.
+func Closest(word string, candidates []string, n int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	word = strings.ToLower(word)
+	limit := MaxDistance(word)
+	matches := []match{}
+	for _, candidate := range candidates {
+		d := Distance(word, strings.ToLower(candidate))
+		if d <= limit && !slices.ContainsFunc(matches, func(m match) bool { return m.candidate == candidate }) {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.candidate, b.candidate)
+	})
+
+	closest := []string{}
+	for _, m := range matches[:min(n, len(matches))] {
+		closest = append(closest, m.candidate)
+	}
+	return closest
+}
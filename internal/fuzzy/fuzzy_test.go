@@ -0,0 +1,60 @@
+package fuzzy
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "", b: "mew", expected: 3},
+		{a: "pikachu", b: "pikachu", expected: 0},
+		{a: "charizrd", b: "charizard", expected: 1},
+		{a: "exlpore", b: "explore", expected: 1},
+		{a: "ekans", b: "arbok", expected: 5},
+		{a: "kitten", b: "sitting", expected: 3},
+		// a swap, then an insertion between the swapped runes, isn't
+		// allowed, so this is 3 rather than 2
+		{a: "ca", b: "abc", expected: 3},
+		{a: "pokémon", b: "pokemon", expected: 1},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := Distance(c.a, c.b); actual != c.expected {
+				t.Errorf("distance from %q to %q is %d; want %d", c.a, c.b, actual, c.expected)
+			}
+			if actual := Distance(c.b, c.a); actual != c.expected {
+				t.Errorf("distance from %q to %q is %d; want %d", c.b, c.a, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	commands := []string{"catch", "explore", "exit", "help", "map", "mapb", "party", "pc"}
+
+	cases := []struct {
+		word     string
+		expected []string
+	}{
+		{word: "cacth", expected: []string{"catch"}},
+		{word: "EXPLROE", expected: []string{"explore"}},
+		{word: "mpa", expected: []string{"map"}},
+		{word: "mapp", expected: []string{"map", "mapb"}},
+		{word: "pt", expected: []string{"pc"}},
+		{word: "squirtle", expected: []string{}},
+	}
+
+	for _, c := range cases {
+		actual := Closest(c.word, commands, 3)
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("closest to %q are %q; want %q", c.word, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+// Package lineedit reads lines from a terminal in raw mode, with the
+// Emacs-style editing keys of readline, history and reverse search.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrInterrupt is returned when the line is abandoned with Ctrl-C.
+var ErrInterrupt = errors.New("interrupted")
+
+// keys with no rune of their own, decoded from escape sequences
+const (
+	keyNone rune = -(iota + 1)
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+	keyWordLeft
+	keyWordRight
+	keyKillWordLeft
+	keyKillWordRight
+)
+
+const (
+	esc       = 0x1b
+	backspace = 0x7f
+)
+
+// the most completions listed at once
+const maxListed = 100
+
+// Completer returns the words that could finish the last word of head, the
+// line up to the cursor. Words that don't start with what's been typed of
+// the last word are left out, so a completer can return every word that
+// fits where it is.
+type Completer func(head string) []string
+
+func ctrl(r rune) rune {
+	return r & 0x1f
+}
+
+// Editor reads lines from in, which should be a terminal in raw mode, and
+// draws them to out as they're edited.
+type Editor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	history *History
+
+	// what Tab completes with, if anything
+	Complete Completer
+
+	// the last text killed, to yank back
+	killed []rune
+}
+
+// New returns an editor browsing history, which may be nil.
+func New(in io.Reader, out io.Writer, history *History) *Editor {
+	if history == nil {
+		history = &History{}
+	}
+	return &Editor{in: bufio.NewReader(in), out: out, history: history}
+}
+
+// line is the state of the line being edited.
+type line struct {
+	prompt string
+	text   []rune
+	pos    int
+
+	// where in history the line came from, or the number of entries for a
+	// new line, and the new line kept aside while browsing
+	histIndex int
+	draft     []rune
+}
+
+// ReadLine shows prompt and returns the line typed, without its newline.
+// Ctrl-C returns ErrInterrupt, and Ctrl-D on an empty line io.EOF. Lines
+// aren't added to the history; that's up to the caller.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	l := &line{prompt: prompt, histIndex: e.history.Len()}
+	e.refresh(l)
+
+	for {
+		k, err := e.readKey()
+		if err != nil {
+			return "", err
+		}
+		if k == ctrl('R') {
+			if k, err = e.search(l); err != nil {
+				return "", err
+			}
+		}
+
+		switch k {
+		case '\r', '\n':
+			e.write("\r\n")
+			return string(l.text), nil
+		case ctrl('C'):
+			e.write("^C\r\n")
+			return "", ErrInterrupt
+		case ctrl('D'):
+			if len(l.text) == 0 {
+				e.write("\r\n")
+				return "", io.EOF
+			}
+			l.delete(l.pos, l.pos+1)
+		case keyDelete:
+			l.delete(l.pos, l.pos+1)
+		case backspace, ctrl('H'):
+			l.delete(l.pos-1, l.pos)
+
+		case ctrl('A'), keyHome:
+			l.pos = 0
+		case ctrl('E'), keyEnd:
+			l.pos = len(l.text)
+		case ctrl('B'), keyLeft:
+			l.pos = max(l.pos-1, 0)
+		case ctrl('F'), keyRight:
+			l.pos = min(l.pos+1, len(l.text))
+		case keyWordLeft:
+			l.pos = wordStart(l.text, l.pos, isWordRune)
+		case keyWordRight:
+			l.pos = wordEnd(l.text, l.pos, isWordRune)
+
+		case ctrl('K'):
+			e.kill(l, l.pos, len(l.text))
+		case ctrl('U'):
+			e.kill(l, 0, l.pos)
+		case ctrl('W'):
+			e.kill(l, wordStart(l.text, l.pos, isNotSpace), l.pos)
+		case keyKillWordLeft:
+			e.kill(l, wordStart(l.text, l.pos, isWordRune), l.pos)
+		case keyKillWordRight:
+			e.kill(l, l.pos, wordEnd(l.text, l.pos, isWordRune))
+		case ctrl('Y'):
+			l.insert(e.killed...)
+
+		case ctrl('P'), keyUp:
+			e.browse(l, l.histIndex-1)
+		case ctrl('N'), keyDown:
+			e.browse(l, l.histIndex+1)
+
+		case '\t':
+			e.complete(l)
+		case ctrl('L'):
+			e.write("\x1b[H\x1b[2J")
+
+		default:
+			if isPrintable(k) {
+				l.insert(k)
+			}
+		}
+		e.refresh(l)
+	}
+}
+
+func (l *line) insert(runes ...rune) {
+	l.text = slices.Insert(l.text, l.pos, runes...)
+	l.pos += len(runes)
+}
+
+// delete removes the runes from start up to end, when they're in the line.
+func (l *line) delete(start, end int) {
+	if start < 0 || end > len(l.text) || start >= end {
+		return
+	}
+	l.text = slices.Delete(l.text, start, end)
+	if l.pos > start {
+		l.pos = max(start, l.pos-(end-start))
+	}
+}
+
+func (e *Editor) kill(l *line, start, end int) {
+	if start >= end {
+		return
+	}
+	e.killed = slices.Clone(l.text[start:end])
+	l.delete(start, end)
+}
+
+// browse replaces the line with history entry i, or the draft when i is
+// just past the last entry.
+func (e *Editor) browse(l *line, i int) {
+	if i < 0 || i > e.history.Len() {
+		return
+	}
+	if l.histIndex == e.history.Len() {
+		l.draft = l.text
+	}
+	l.histIndex = i
+	if i == e.history.Len() {
+		l.text = l.draft
+	} else {
+		l.text = []rune(e.history.entries[i])
+	}
+	l.pos = len(l.text)
+}
+
+// complete finishes the word before the cursor as far as the completions
+// agree, listing them when they don't agree any further.
+func (e *Editor) complete(l *line) {
+	if e.Complete == nil {
+		return
+	}
+	// the word being completed runs up to the cursor, and is empty right
+	// after a space
+	start := l.pos
+	for start > 0 && isNotSpace(l.text[start-1]) {
+		start--
+	}
+	partial := string(l.text[start:l.pos])
+
+	matches := []string{}
+	for _, word := range e.Complete(string(l.text[:l.pos])) {
+		if strings.HasPrefix(word, partial) && !slices.Contains(matches, word) {
+			matches = append(matches, word)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		e.write("\a")
+	case 1:
+		l.insert([]rune(matches[0][len(partial):])...)
+		if l.pos == len(l.text) || !unicode.IsSpace(l.text[l.pos]) {
+			l.insert(' ')
+		}
+	default:
+		if prefix := commonPrefix(matches); len(prefix) > len(partial) {
+			l.insert([]rune(prefix[len(partial):])...)
+			return
+		}
+		slices.Sort(matches)
+		listed := strings.Join(matches[:min(len(matches), maxListed)], "  ")
+		if len(matches) > maxListed {
+			listed += fmt.Sprintf("  ...and %d more", len(matches)-maxListed)
+		}
+		e.write("\r\n" + listed + "\r\n")
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// search runs a reverse incremental search through the history for Ctrl-R,
+// putting the match in the line. It returns the key that ended the search,
+// which still needs handling, or keyNone if the search was cancelled.
+func (e *Editor) search(l *line) (rune, error) {
+	query := []rune{}
+	match := -1
+	failed := false
+
+	find := func(from int) {
+		for i := min(from, e.history.Len()-1); i >= 0; i-- {
+			if strings.Contains(e.history.entries[i], string(query)) {
+				match, failed = i, false
+				return
+			}
+		}
+		failed = true
+	}
+
+	for {
+		found := ""
+		if match >= 0 {
+			found = e.history.entries[match]
+		}
+		status := "reverse-i-search"
+		if failed {
+			status = "failing " + status
+		}
+		e.write(fmt.Sprintf("\r(%s)`%s': %s\x1b[K", status, string(query), found))
+
+		k, err := e.readKey()
+		if err != nil {
+			return keyNone, err
+		}
+
+		switch {
+		case k == ctrl('R'):
+			if match >= 0 {
+				find(match - 1)
+			}
+		case k == backspace || k == ctrl('H'):
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				match = -1
+				find(e.history.Len() - 1)
+			}
+		case k == ctrl('G') || k == ctrl('C'):
+			e.refresh(l)
+			return keyNone, nil
+		case isPrintable(k):
+			query = append(query, k)
+			if match < 0 {
+				find(e.history.Len() - 1)
+			} else {
+				find(match)
+			}
+		default:
+			if match >= 0 {
+				l.histIndex = match
+				l.text = []rune(found)
+				l.pos = len(l.text)
+			}
+			return k, nil
+		}
+	}
+}
+
+// refresh redraws the line and puts the cursor back where it belongs.
+func (e *Editor) refresh(l *line) {
+	var b strings.Builder
+	b.WriteString("\r" + l.prompt + string(l.text) + "\x1b[K")
+	if back := len(l.text) - l.pos; back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+	e.write(b.String())
+}
+
+func (e *Editor) write(s string) {
+	io.WriteString(e.out, s)
+}
+
+// readKey reads a key press, decoding the escape sequences terminals send
+// for arrows and the like, and Alt (Meta) combinations.
+func (e *Editor) readKey() (rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil || r != esc {
+		return r, err
+	}
+
+	r, _, err = e.in.ReadRune()
+	if err != nil {
+		return keyNone, err
+	}
+	switch r {
+	case 'b', 'B':
+		return keyWordLeft, nil
+	case 'f', 'F':
+		return keyWordRight, nil
+	case 'd', 'D':
+		return keyKillWordRight, nil
+	case backspace, ctrl('H'):
+		return keyKillWordLeft, nil
+	case '[', 'O':
+	default:
+		return keyNone, nil
+	}
+
+	// a control sequence: parameters, then a final byte from @ to ~
+	params := []byte{}
+	for {
+		b, err := e.in.ReadByte()
+		if err != nil {
+			return keyNone, err
+		}
+		if b >= '@' && b <= '~' {
+			return csiKey(string(params), b), nil
+		}
+		params = append(params, b)
+	}
+}
+
+func csiKey(params string, final byte) rune {
+	// Ctrl or Alt with an arrow moves by words, like ESC [ 1 ; 5 C
+	modified := strings.HasSuffix(params, ";5") || strings.HasSuffix(params, ";3")
+
+	switch final {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	case 'C':
+		if modified {
+			return keyWordRight
+		}
+		return keyRight
+	case 'D':
+		if modified {
+			return keyWordLeft
+		}
+		return keyLeft
+	case 'H':
+		return keyHome
+	case 'F':
+		return keyEnd
+	case '~':
+		switch params {
+		case "1", "7":
+			return keyHome
+		case "4", "8":
+			return keyEnd
+		case "3":
+			return keyDelete
+		}
+	}
+	return keyNone
+}
+
+func isPrintable(r rune) bool {
+	return r >= ' ' && r != backspace
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNotSpace(r rune) bool {
+	return !unicode.IsSpace(r)
+}
+
+// wordStart returns where the word before pos starts, skipping anything
+// between it and pos. Words are runs of runes inWord matches.
+func wordStart(text []rune, pos int, inWord func(rune) bool) int {
+	for pos > 0 && !inWord(text[pos-1]) {
+		pos--
+	}
+	for pos > 0 && inWord(text[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// wordEnd returns where the word after pos ends.
+func wordEnd(text []rune, pos int, inWord func(rune) bool) int {
+	for pos < len(text) && !inWord(text[pos]) {
+		pos++
+	}
+	for pos < len(text) && inWord(text[pos]) {
+		pos++
+	}
+	return pos
+}
@@ -0,0 +1,136 @@
+package lineedit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	history := &History{entries: []string{"explore pastoria-city-area", "catch pikachu", "map"}}
+
+	cases := []struct {
+		keys     string
+		expected string
+	}{
+		{keys: "map\r", expected: "map"},
+		// Ctrl-A to insert at the start, Ctrl-E back to the end
+		{keys: "ap\x01m\x05b\r", expected: "mapb"},
+		// the left arrow and backspace
+		{keys: "catchh pikachu\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x7f\r", expected: "catch pikachu"},
+		// Alt-B back a word, then the delete key
+		{keys: "catch ppikachu\x1bb\x1b[3~\r", expected: "catch pikachu"},
+		// Ctrl-Left to the start of a word
+		{keys: "explore area\x1b[1;5Dcity-\r", expected: "explore city-area"},
+		// Ctrl-W kills back to whitespace, and Ctrl-Y yanks it back
+		{keys: "catch pikachu\x17\x17pidgey \x19\r", expected: "pidgey catch "},
+		// Ctrl-U kills the line before the cursor, Ctrl-K after it
+		{keys: "inspect 1\x1b[D\x15party \x05 2\x01\x1bf\x0b\r", expected: "party"},
+		// Alt-D kills the next word
+		{keys: "catch mew two\x01\x1bf\x1bd\r", expected: "catch two"},
+		// the up arrow browses history, and down returns to the draft
+		{keys: "\x1b[A\x1b[A\r", expected: "catch pikachu"},
+		{keys: "wan\x1b[A\x1b[Bder\r", expected: "wander"},
+		// Ctrl-R searches back, and again for older matches
+		{keys: "\x12a\r", expected: "map"},
+		{keys: "\x12a\x12\r", expected: "catch pikachu"},
+		{keys: "\x12a\x12\x12\x12\r", expected: "explore pastoria-city-area"},
+		// keys other than Enter accept the match and are handled
+		{keys: "\x12past\x05 --all\r", expected: "explore pastoria-city-area --all"},
+		// Ctrl-G cancels the search, keeping the line
+		{keys: "where\x12map\x07 ditto\r", expected: "where ditto"},
+		{keys: "pokédex\r", expected: "pokédex"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			var out strings.Builder
+			e := New(strings.NewReader(c.keys), &out, history)
+			actual, err := e.ReadLine("> ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("read %q; want %q", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestReadLineEndings(t *testing.T) {
+	cases := []struct {
+		keys     string
+		expected error
+	}{
+		{keys: "catch\x03", expected: ErrInterrupt},
+		{keys: "\x04", expected: io.EOF},
+		{keys: "map", expected: io.EOF},
+	}
+
+	for _, c := range cases {
+		var out strings.Builder
+		_, err := New(strings.NewReader(c.keys), &out, nil).ReadLine("> ")
+		if !errors.Is(err, c.expected) {
+			t.Errorf("reading %q returned %v; want %v", c.keys, err, c.expected)
+		}
+	}
+}
+
+func TestRefreshPlacesCursor(t *testing.T) {
+	var out strings.Builder
+	e := New(strings.NewReader("map\x02\x02\r"), &out, nil)
+	if _, err := e.ReadLine("> "); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "\r> map\x1b[K\x1b[2D") {
+		t.Errorf("drew %q; want the cursor two back from the end", out.String())
+	}
+}
+
+func TestComplete(t *testing.T) {
+	complete := func(head string) []string {
+		if !strings.Contains(head, " ") {
+			return []string{"catch", "party", "pc", "pokedex"}
+		}
+		return []string{"pidgey", "pidgeotto", "pikachu"}
+	}
+
+	cases := []struct {
+		keys     string
+		expected string
+		listed   bool
+	}{
+		{keys: "ca\t\r", expected: "catch "},
+		{keys: "po\t\r", expected: "pokedex "},
+		{keys: "catch pidg\t\r", expected: "catch pidge"},
+		{keys: "catch pi\t\r", expected: "catch pi", listed: true},
+		{keys: "catch x\t\r", expected: "catch x"},
+		// after a space, the next word is completed from nothing
+		{keys: "catch \t\r", expected: "catch pi"},
+		{keys: "catch \t\t\r", expected: "catch pi", listed: true},
+		// completing mid-line doesn't add a second space
+		{keys: "pa 1\x01\x06\x06\t\r", expected: "party 1"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			var out strings.Builder
+			e := New(strings.NewReader(c.keys), &out, nil)
+			e.Complete = complete
+			actual, err := e.ReadLine("> ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("read %q; want %q", actual, c.expected)
+			}
+			listed := strings.Contains(out.String(), "pidgeotto  pidgey  pikachu")
+			if listed != c.listed {
+				t.Errorf("listed completions: %v; want %v", listed, c.listed)
+			}
+		})
+	}
+}
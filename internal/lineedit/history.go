@@ -0,0 +1,144 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// History is the lines entered before, oldest first, kept in a file so
+// they last between sessions.
+type History struct {
+	path    string
+	max     int
+	entries []string
+}
+
+// LoadHistory reads the history kept at path, which needn't exist yet,
+// remembering up to max lines. With no path, history lasts only as long as
+// the session.
+func LoadHistory(path string, max int) (*History, error) {
+	h := History{path: path, max: max}
+	if path == "" {
+		return &h, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.entries = append(h.entries, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// the file only grows as lines are added, so it's trimmed here
+	if len(h.entries) > max {
+		h.entries = h.entries[len(h.entries)-max:]
+		if err := h.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &h, nil
+}
+
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Entries returns the lines in the history, oldest first. Entry n, as used
+// by Expand, is Entries()[n-1].
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Add appends line to the history, unless it's blank or repeats the last
+// line.
+func (h *History) Add(line string) error {
+	if strings.TrimSpace(line) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == line) {
+		return nil
+	}
+
+	h.entries = append(h.entries, line)
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Expand replaces the history references starting words in line: !! with
+// the last line, and !n with entry n.
+func (h *History) Expand(line string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(line); {
+		if line[i] != '!' || (i > 0 && !strings.ContainsRune(" \t;", rune(line[i-1]))) {
+			b.WriteByte(line[i])
+			i++
+			continue
+		}
+
+		rest := line[i+1:]
+		if strings.HasPrefix(rest, "!") {
+			if len(h.entries) == 0 {
+				return "", errors.New("!!: event not found")
+			}
+			b.WriteString(h.entries[len(h.entries)-1])
+			i += 2
+			continue
+		}
+
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 {
+			b.WriteByte('!')
+			i++
+			continue
+		}
+		n, _ := strconv.Atoi(rest[:digits])
+		if n < 1 || n > len(h.entries) {
+			return "", fmt.Errorf("!%s: event not found", rest[:digits])
+		}
+		b.WriteString(h.entries[n-1])
+		i += 1 + digits
+	}
+
+	return b.String(), nil
+}
+
+func (h *History) rewrite() error {
+	contents := strings.Join(h.entries, "\n") + "\n"
+	tmpPath := h.path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(contents), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, h.path)
+}
@@ -0,0 +1,77 @@
+package lineedit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHistoryPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"map", "map", " ", "explore", "catch pikachu", "inspect 1"} {
+		if err := h.Add(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []string{"explore", "catch pikachu", "inspect 1"}
+	if !slices.Equal(h.Entries(), expected) {
+		t.Errorf("history is %q; want %q", h.Entries(), expected)
+	}
+
+	// the file keeps every line until it's loaded again and trimmed
+	h, err = LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(h.Entries(), expected) {
+		t.Errorf("reloaded history is %q; want %q", h.Entries(), expected)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "explore\ncatch pikachu\ninspect 1\n" {
+		t.Errorf("history file holds %q", contents)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	h := &History{entries: []string{"explore", "catch pikachu", "inspect 1"}}
+
+	cases := []struct {
+		line     string
+		expected string
+		err      bool
+	}{
+		{line: "!!", expected: "inspect 1"},
+		{line: "!2", expected: "catch pikachu"},
+		{line: "!1 ; !!", expected: "explore ; inspect 1"},
+		{line: "rename 1 \"Zap!!\"", expected: "rename 1 \"Zap!!\""},
+		{line: "!", expected: "!"},
+		{line: "!4", err: true},
+		{line: "!0", err: true},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual, err := h.Expand(c.line)
+			if (err != nil) != c.err {
+				t.Fatalf("expanding %q returned error %v", c.line, err)
+			}
+			if actual != c.expected {
+				t.Errorf("expanded %q to %q; want %q", c.line, actual, c.expected)
+			}
+		})
+	}
+
+	if _, err := (&History{}).Expand("!!"); err == nil {
+		t.Error("expanded !! with no history")
+	}
+}
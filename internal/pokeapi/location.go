@@ -0,0 +1,86 @@
+package pokeapi
+
+import "github.com/chuckatc/pokedexcli/internal/pokecache"
+
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     string             `json:"next"`
+	Previous string             `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+type RegionData struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []NamedAPIResource `json:"locations"`
+	MainGeneration NamedAPIResource   `json:"main_generation"`
+	Pokedexes      []NamedAPIResource `json:"pokedexes"`
+	VersionGroups  []NamedAPIResource `json:"version_groups"`
+}
+
+type LocationData struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Region NamedAPIResource   `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+}
+
+func GetRegions(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "region/"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetLocationList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "location/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetLocationAreaList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "location-area/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetRegion(regionName string, cache *pokecache.Cache) (RegionData, error) {
+	var data RegionData
+	url := baseUrl + "region/" + regionName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return RegionData{}, err
+	}
+
+	return data, nil
+}
+
+func GetLocation(locationName string, cache *pokecache.Cache) (LocationData, error) {
+	var data LocationData
+	url := baseUrl + "location/" + locationName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return LocationData{}, err
+	}
+
+	return data, nil
+}
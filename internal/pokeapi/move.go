@@ -0,0 +1,71 @@
+package pokeapi
+
+import "github.com/chuckatc/pokedexcli/internal/pokecache"
+
+type MoveData struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Accuracy    int              `json:"accuracy"`
+	Power       int              `json:"power"`
+	PP          int              `json:"pp"`
+	Priority    int              `json:"priority"`
+	Type        NamedAPIResource `json:"type"`
+	DamageClass NamedAPIResource `json:"damage_class"`
+}
+
+type TypeData struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+		HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+		DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+		NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+		HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+		DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func GetMove(moveName string, cache *pokecache.Cache) (MoveData, error) {
+	var data MoveData
+	url := baseUrl + "move/" + moveName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return MoveData{}, err
+	}
+
+	return data, nil
+}
+
+func GetMoveList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "move/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetItemList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "item/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetType(typeName string, cache *pokecache.Cache) (TypeData, error) {
+	var data TypeData
+	url := baseUrl + "type/" + typeName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return TypeData{}, err
+	}
+
+	return data, nil
+}
@@ -2,8 +2,9 @@ package pokeapi
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
-	"log"
 	"net/http"
 
 	"github.com/chuckatc/pokedexcli/internal/pokecache"
@@ -11,6 +12,65 @@ import (
 
 const baseUrl = "https://pokeapi.co/api/v2/"
 
+// ErrNotFound is wrapped by the error for a resource the PokeAPI doesn't
+// have, like a misspelled Pokemon.
+var ErrNotFound = errors.New("not found")
+
+// getJSON decodes the resource at url into data, using the cache when it
+// holds a fresh copy and populating it after a successful fetch.
+func getJSON(url string, cache *pokecache.Cache, data any) error {
+	if cached, ok := cache.Get(url); ok {
+		return json.Unmarshal(cached, data)
+	}
+
+	body, err := fetch(url)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, data); err != nil {
+		return err
+	}
+
+	cache.Add(url, body)
+
+	return nil
+}
+
+// GetFile returns the raw contents of a file PokeAPI links to, like a sprite
+// or a cry, through the cache.
+func GetFile(url string, cache *pokecache.Cache) ([]byte, error) {
+	if cached, ok := cache.Get(url); ok {
+		return cached, nil
+	}
+
+	body, err := fetch(url)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Add(url, body)
+
+	return body, nil
+}
+
+func fetch(url string) ([]byte, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
 type LocationAreaData struct {
 	Count    int    `json:"count"`
 	Next     string `json:"next"`
@@ -21,7 +81,7 @@ type LocationAreaData struct {
 	} `json:"results"`
 }
 
-func GetMap(nextUrl string, cache *pokecache.Cache) LocationAreaData {
+func GetMap(nextUrl string, cache *pokecache.Cache) (LocationAreaData, error) {
 	var data LocationAreaData
 
 	url := baseUrl + "location-area/"
@@ -29,36 +89,11 @@ func GetMap(nextUrl string, cache *pokecache.Cache) LocationAreaData {
 		url = nextUrl
 	}
 
-	mapData, ok := cache.Get(url)
-	if ok {
-		err := json.Unmarshal(mapData, &data)
-		if err != nil {
-			log.Fatal(err)
-		}
-		// fmt.Println("\t\tCACHE HIT")
-		return data
-	}
-
-	res, err := http.Get(url)
-	if err != nil {
-		log.Fatal(err)
+	if err := getJSON(url, cache, &data); err != nil {
+		return LocationAreaData{}, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	cache.Add(url, body)
-
-	// fmt.Println("\t\tCACHE MISS")
-	return data
+	return data, nil
 }
 
 type LocationAreaDetailData struct {
@@ -89,63 +124,42 @@ type LocationAreaDetailData struct {
 		} `json:"language"`
 		Name string `json:"name"`
 	} `json:"names"`
-	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
-		VersionDetails []struct {
-			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-				MinLevel int `json:"min_level"`
-			} `json:"encounter_details"`
-			MaxChance int `json:"max_chance"`
-			Version   struct {
+	PokemonEncounters []PokemonEncounter `json:"pokemon_encounters"`
+}
+
+type PokemonEncounter struct {
+	Pokemon struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"pokemon"`
+	VersionDetails []struct {
+		EncounterDetails []struct {
+			Chance          int   `json:"chance"`
+			ConditionValues []any `json:"condition_values"`
+			MaxLevel        int   `json:"max_level"`
+			Method          struct {
 				Name string `json:"name"`
 				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"pokemon_encounters"`
+			} `json:"method"`
+			MinLevel int `json:"min_level"`
+		} `json:"encounter_details"`
+		MaxChance int `json:"max_chance"`
+		Version   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"version"`
+	} `json:"version_details"`
 }
 
-func GetExploreData(locationArea string, cache *pokecache.Cache) LocationAreaDetailData {
+func GetExploreData(locationArea string, cache *pokecache.Cache) (LocationAreaDetailData, error) {
 	var data LocationAreaDetailData
 	url := baseUrl + "location-area/" + locationArea
 
-	exploreData, ok := cache.Get(url)
-	if ok {
-		err := json.Unmarshal(exploreData, &data)
-		if err != nil {
-			log.Fatal(err)
-		}
-		return data
+	if err := getJSON(url, cache, &data); err != nil {
+		return LocationAreaDetailData{}, err
 	}
 
-	res, err := http.Get(url)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	cache.Add(url, body)
-
-	return data
+	return data, nil
 }
 
 type PokemonData struct {
@@ -422,32 +436,47 @@ func GetPokemonData(pokemonName string, cache *pokecache.Cache) (PokemonData, er
 	var data PokemonData
 	url := baseUrl + "pokemon/" + pokemonName
 
-	pokemonData, ok := cache.Get(url)
-	if ok {
-		err := json.Unmarshal(pokemonData, &data)
-		if err != nil {
-			return PokemonData{}, err
-		}
-		return data, nil
-	}
-
-	res, err := http.Get(url)
-	if err != nil {
+	if err := getJSON(url, cache, &data); err != nil {
 		return PokemonData{}, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return PokemonData{}, err
-	}
+	return data, nil
+}
 
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		return PokemonData{}, err
+func GetPokemonList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "pokemon/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
 	}
 
-	cache.Add(url, body)
+	return data, nil
+}
+
+type LocationAreaEncounter struct {
+	LocationArea   NamedAPIResource `json:"location_area"`
+	VersionDetails []struct {
+		MaxChance        int              `json:"max_chance"`
+		Version          NamedAPIResource `json:"version"`
+		EncounterDetails []struct {
+			MinLevel        int                `json:"min_level"`
+			MaxLevel        int                `json:"max_level"`
+			ConditionValues []NamedAPIResource `json:"condition_values"`
+			Chance          int                `json:"chance"`
+			Method          NamedAPIResource   `json:"method"`
+		} `json:"encounter_details"`
+	} `json:"version_details"`
+}
+
+// GetPokemonEncounters follows a Pokemon's LocationAreaEncounters URL to
+// where it can be encountered.
+func GetPokemonEncounters(encountersUrl string, cache *pokecache.Cache) ([]LocationAreaEncounter, error) {
+	var data []LocationAreaEncounter
+
+	if err := getJSON(encountersUrl, cache, &data); err != nil {
+		return nil, err
+	}
 
 	return data, nil
 }
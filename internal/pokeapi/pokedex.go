@@ -0,0 +1,92 @@
+package pokeapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/chuckatc/pokedexcli/internal/pokecache"
+)
+
+type PokedexData struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	IsMainSeries   bool             `json:"is_main_series"`
+	Region         NamedAPIResource `json:"region"`
+	PokemonEntries []struct {
+		EntryNumber    int              `json:"entry_number"`
+		PokemonSpecies NamedAPIResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+}
+
+type GenerationData struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+}
+
+// IDFromURL returns the ID at the end of a resource URL, like the 25 in
+// https://pokeapi.co/api/v2/pokemon-species/25/, or 0 if there isn't one.
+func IDFromURL(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func GetPokedex(pokedexName string, cache *pokecache.Cache) (PokedexData, error) {
+	var data PokedexData
+	url := baseUrl + "pokedex/" + pokedexName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return PokedexData{}, err
+	}
+
+	return data, nil
+}
+
+func GetPokedexList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "pokedex/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetPokemonSpeciesList(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "pokemon-species/?limit=100000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetGenerations(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "generation/"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetGeneration(generationName string, cache *pokecache.Cache) (GenerationData, error) {
+	var data GenerationData
+	url := baseUrl + "generation/" + generationName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return GenerationData{}, err
+	}
+
+	return data, nil
+}
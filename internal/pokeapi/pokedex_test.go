@@ -0,0 +1,22 @@
+package pokeapi
+
+import "testing"
+
+func TestIDFromURL(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected int
+	}{
+		{url: "https://pokeapi.co/api/v2/pokemon-species/25/", expected: 25},
+		{url: "https://pokeapi.co/api/v2/pokemon/10034", expected: 10034},
+		{url: "https://pokeapi.co/api/v2/pokemon/pikachu/", expected: 0},
+		{url: "", expected: 0},
+	}
+
+	for _, c := range cases {
+		actual := IDFromURL(c.url)
+		if actual != c.expected {
+			t.Errorf("IDFromURL(%q) is %d; want %d", c.url, actual, c.expected)
+		}
+	}
+}
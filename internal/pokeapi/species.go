@@ -0,0 +1,75 @@
+package pokeapi
+
+import "github.com/chuckatc/pokedexcli/internal/pokecache"
+
+type PokemonSpeciesData struct {
+	ID         int              `json:"id"`
+	Name       string           `json:"name"`
+	Generation NamedAPIResource `json:"generation"`
+	GrowthRate NamedAPIResource `json:"growth_rate"`
+	Varieties  []struct {
+		IsDefault bool             `json:"is_default"`
+		Pokemon   NamedAPIResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+type GrowthRateData struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Formula string `json:"formula"`
+	Levels  []struct {
+		Level      int `json:"level"`
+		Experience int `json:"experience"`
+	} `json:"levels"`
+}
+
+type NatureData struct {
+	ID            int              `json:"id"`
+	Name          string           `json:"name"`
+	IncreasedStat NamedAPIResource `json:"increased_stat"`
+	DecreasedStat NamedAPIResource `json:"decreased_stat"`
+}
+
+func GetPokemonSpecies(speciesName string, cache *pokecache.Cache) (PokemonSpeciesData, error) {
+	var data PokemonSpeciesData
+	url := baseUrl + "pokemon-species/" + speciesName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return PokemonSpeciesData{}, err
+	}
+
+	return data, nil
+}
+
+func GetGrowthRate(growthRateName string, cache *pokecache.Cache) (GrowthRateData, error) {
+	var data GrowthRateData
+	url := baseUrl + "growth-rate/" + growthRateName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return GrowthRateData{}, err
+	}
+
+	return data, nil
+}
+
+func GetNatures(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "nature/?limit=100"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetNature(natureName string, cache *pokecache.Cache) (NatureData, error) {
+	var data NatureData
+	url := baseUrl + "nature/" + natureName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NatureData{}, err
+	}
+
+	return data, nil
+}
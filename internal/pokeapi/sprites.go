@@ -0,0 +1,92 @@
+package pokeapi
+
+type spriteSet struct {
+	frontDefault string
+	frontShiny   string
+	backDefault  string
+	backShiny    string
+}
+
+func (set spriteSet) pick(shiny, back bool) string {
+	switch {
+	case back && shiny:
+		return set.backShiny
+	case back:
+		return set.backDefault
+	case shiny:
+		return set.frontShiny
+	default:
+		return set.frontDefault
+	}
+}
+
+// SpriteURL returns the sprite as it appeared in the given game version,
+// falling back to the default sprite for versions (or variants, like shiny
+// sprites before generation II) that don't have their own.
+func (data PokemonData) SpriteURL(version string, shiny, back bool) string {
+	if url := data.versionSprites(version).pick(shiny, back); url != "" {
+		return url
+	}
+
+	sprites := data.Sprites
+	return spriteSet{
+		frontDefault: sprites.FrontDefault,
+		frontShiny:   sprites.FrontShiny,
+		backDefault:  sprites.BackDefault,
+		backShiny:    sprites.BackShiny,
+	}.pick(shiny, back)
+}
+
+func (data PokemonData) versionSprites(version string) spriteSet {
+	versions := data.Sprites.Versions
+
+	switch version {
+	case "red", "blue":
+		s := versions.GenerationI.RedBlue
+		return spriteSet{frontDefault: s.FrontDefault, backDefault: s.BackDefault}
+	case "yellow":
+		s := versions.GenerationI.Yellow
+		return spriteSet{frontDefault: s.FrontDefault, backDefault: s.BackDefault}
+	case "gold":
+		s := versions.GenerationIi.Gold
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "silver":
+		s := versions.GenerationIi.Silver
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "crystal":
+		s := versions.GenerationIi.Crystal
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "ruby", "sapphire":
+		s := versions.GenerationIii.RubySapphire
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "emerald":
+		s := versions.GenerationIii.Emerald
+		return spriteSet{frontDefault: s.FrontDefault, frontShiny: s.FrontShiny}
+	case "firered", "leafgreen":
+		s := versions.GenerationIii.FireredLeafgreen
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "diamond", "pearl":
+		s := versions.GenerationIv.DiamondPearl
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "platinum":
+		s := versions.GenerationIv.Platinum
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "heartgold", "soulsilver":
+		s := versions.GenerationIv.HeartgoldSoulsilver
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "black", "white", "black-2", "white-2":
+		s := versions.GenerationV.BlackWhite
+		return spriteSet{s.FrontDefault, s.FrontShiny, s.BackDefault, s.BackShiny}
+	case "x", "y":
+		s := versions.GenerationVi.XY
+		return spriteSet{frontDefault: s.FrontDefault, frontShiny: s.FrontShiny}
+	case "omega-ruby", "alpha-sapphire":
+		s := versions.GenerationVi.OmegarubyAlphasapphire
+		return spriteSet{frontDefault: s.FrontDefault, frontShiny: s.FrontShiny}
+	case "ultra-sun", "ultra-moon":
+		s := versions.GenerationVii.UltraSunUltraMoon
+		return spriteSet{frontDefault: s.FrontDefault, frontShiny: s.FrontShiny}
+	default:
+		return spriteSet{}
+	}
+}
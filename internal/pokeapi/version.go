@@ -0,0 +1,31 @@
+package pokeapi
+
+import "github.com/chuckatc/pokedexcli/internal/pokecache"
+
+type VersionData struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+func GetVersions(cache *pokecache.Cache) (NamedAPIResourceList, error) {
+	var data NamedAPIResourceList
+	url := baseUrl + "version/?limit=1000"
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return NamedAPIResourceList{}, err
+	}
+
+	return data, nil
+}
+
+func GetVersion(versionName string, cache *pokecache.Cache) (VersionData, error) {
+	var data VersionData
+	url := baseUrl + "version/" + versionName
+
+	if err := getJSON(url, cache, &data); err != nil {
+		return VersionData{}, err
+	}
+
+	return data, nil
+}
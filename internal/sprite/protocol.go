@@ -0,0 +1,249 @@
+package sprite
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+// Protocol is how sprites reach the terminal: drawn with characters, or sent
+// as an image with one of the inline image protocols.
+type Protocol int
+
+const (
+	Blocks Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+var protocolNames = []string{"blocks", "kitty", "iterm2", "sixel"}
+
+func (p Protocol) String() string {
+	return protocolNames[p]
+}
+
+func ParseProtocol(name string) (Protocol, bool) {
+	i := slices.Index(protocolNames, name)
+	return Protocol(i), i >= 0
+}
+
+// DA1 asks the terminal for its primary device attributes.
+const DA1 = "\x1b[c"
+
+// the device attribute terminals report for Sixel graphics
+const sixelAttribute = "4"
+
+// the largest payload of a single Kitty graphics escape
+const kittyChunkSize = 4096
+
+// the most color registers Sixel terminals reliably offer
+const maxSixelColors = 256
+
+// DetectProtocol picks the best protocol the terminal supports, first from
+// environment variables known to identify terminals, then by asking the
+// terminal for its device attributes with da1. da1 may be nil when there's
+// no terminal to ask.
+func DetectProtocol(getenv func(string) string, da1 func() (string, error)) Protocol {
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", getenv("TERM") == "xterm-kitty",
+		getenv("TERM") == "xterm-ghostty":
+		return Kitty
+	case getenv("TERM_PROGRAM") == "iTerm.app", getenv("LC_TERMINAL") == "iTerm2",
+		getenv("TERM_PROGRAM") == "WezTerm":
+		return ITerm2
+	}
+
+	if da1 != nil {
+		if reply, err := da1(); err == nil && hasSixel(reply) {
+			return Sixel
+		}
+	}
+
+	return Blocks
+}
+
+// hasSixel reports whether a DA1 reply, like "\x1b[?62;4;22c", lists the
+// Sixel attribute.
+func hasSixel(reply string) bool {
+	reply = strings.TrimPrefix(reply, "\x1b[?")
+	reply = strings.TrimSuffix(reply, "c")
+	return slices.Contains(strings.Split(reply, ";"), sixelAttribute)
+}
+
+// Draw shows a PNG sprite using protocol, falling back to drawing it with
+// half blocks in mode.
+func Draw(w io.Writer, pngData []byte, protocol Protocol, mode ansi.ColorMode) error {
+	switch protocol {
+	case Kitty:
+		return EncodeKitty(w, pngData)
+	case ITerm2:
+		return EncodeITerm2(w, pngData)
+	}
+
+	img, err := Decode(pngData)
+	if err != nil {
+		return err
+	}
+	if protocol == Sixel {
+		return EncodeSixel(w, img)
+	}
+	return Render(w, img, mode)
+}
+
+// EncodeKitty sends a PNG with the Kitty graphics protocol, split into
+// chunks since terminals limit the size of each escape sequence.
+func EncodeKitty(w io.Writer, pngData []byte) error {
+	bw := bufio.NewWriter(w)
+	payload := base64.StdEncoding.EncodeToString(pngData)
+
+	for i := 0; i == 0 || i < len(payload); i += kittyChunkSize {
+		chunk := payload[i:min(i+kittyChunkSize, len(payload))]
+		more := 0
+		if i+kittyChunkSize < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(bw, "\x1b_Gf=100,a=T,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(bw, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	bw.WriteByte('\n')
+
+	return bw.Flush()
+}
+
+// EncodeITerm2 sends a PNG as an iTerm2 inline image.
+func EncodeITerm2(w io.Writer, pngData []byte) error {
+	_, err := fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;preserveAspectRatio=1:%s\a\n",
+		len(pngData), base64.StdEncoding.EncodeToString(pngData))
+	return err
+}
+
+// EncodeSixel draws img as Sixel graphics, leaving transparent pixels
+// unpainted. Sprites with more colors than there are registers are reduced
+// to the 6x6x6 color cube.
+func EncodeSixel(w io.Writer, img image.Image) error {
+	bounds := trim(img)
+	bw := bufio.NewWriter(w)
+
+	palette, index := sixelPalette(img, bounds)
+
+	fmt.Fprintf(bw, "\x1bP0;1q\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for i, c := range palette {
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, percent(c.R), percent(c.G), percent(c.B))
+	}
+
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += 6 {
+		// the sixels of the band for each color, across its width
+		bands := make([][]byte, len(palette))
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			for bit := range 6 {
+				y := top + bit
+				if y >= bounds.Max.Y || !visible(img.At(x, y)) {
+					continue
+				}
+				i := index(img.At(x, y))
+				if bands[i] == nil {
+					bands[i] = make([]byte, bounds.Dx())
+				}
+				bands[i][x-bounds.Min.X] |= 1 << bit
+			}
+		}
+
+		first := true
+		for i, band := range bands {
+			if band == nil {
+				continue
+			}
+			if !first {
+				bw.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(bw, "#%d", i)
+			writeSixels(bw, band)
+		}
+		bw.WriteByte('-')
+	}
+	bw.WriteString("\x1b\\\n")
+
+	return bw.Flush()
+}
+
+// sixelPalette lists the colors to define, and returns a function giving
+// the register for each pixel's color.
+func sixelPalette(img image.Image, bounds image.Rectangle) ([]color.NRGBA, func(color.Color) int) {
+	registers := map[color.NRGBA]int{}
+	palette := []color.NRGBA{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.At(x, y)
+			if !visible(c) {
+				continue
+			}
+			n := opaque(c)
+			if _, ok := registers[n]; !ok {
+				registers[n] = len(palette)
+				palette = append(palette, n)
+			}
+		}
+	}
+
+	if len(palette) <= maxSixelColors {
+		return palette, func(c color.Color) int {
+			return registers[opaque(c)]
+		}
+	}
+
+	cube := make([]color.NRGBA, 0, 216)
+	for r := range 6 {
+		for g := range 6 {
+			for b := range 6 {
+				cube = append(cube, color.NRGBA{uint8(r * 51), uint8(g * 51), uint8(b * 51), 255})
+			}
+		}
+	}
+	return cube, func(c color.Color) int {
+		r, g, b := rgb(c)
+		return ansi.Xterm256(r, g, b) - 16
+	}
+}
+
+func opaque(c color.Color) color.NRGBA {
+	r, g, b := rgb(c)
+	return color.NRGBA{r, g, b, 255}
+}
+
+func percent(v uint8) int {
+	return (int(v)*100 + 127) / 255
+}
+
+// writeSixels writes one color's sixels for a band, compressing runs with
+// the repeat introducer.
+func writeSixels(w *bufio.Writer, band []byte) {
+	for i := 0; i < len(band); {
+		run := 1
+		for i+run < len(band) && band[i+run] == band[i] {
+			run++
+		}
+		sixel := band[i] + '?'
+		if run > 3 {
+			w.WriteString("!" + strconv.Itoa(run))
+			w.WriteByte(sixel)
+		} else {
+			for range run {
+				w.WriteByte(sixel)
+			}
+		}
+		i += run
+	}
+}
@@ -0,0 +1,109 @@
+package sprite
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestEncodeGolden(t *testing.T) {
+	pngData, err := os.ReadFile(filepath.Join("testdata", "sprite.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		golden string
+		encode func(*bytes.Buffer) error
+	}{
+		{
+			golden: "kitty.golden",
+			encode: func(buf *bytes.Buffer) error { return EncodeKitty(buf, pngData) },
+		},
+		{
+			golden: "iterm2.golden",
+			encode: func(buf *bytes.Buffer) error { return EncodeITerm2(buf, pngData) },
+		},
+		{
+			golden: "sixel.golden",
+			encode: func(buf *bytes.Buffer) error {
+				img, err := Decode(pngData)
+				if err != nil {
+					return err
+				}
+				return EncodeSixel(buf, img)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.encode(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join("testdata", c.golden)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), expected) {
+				t.Errorf("encoded %q; want %q", buf.Bytes(), expected)
+			}
+		})
+	}
+}
+
+func TestEncodeKittyChunks(t *testing.T) {
+	// 4000 bytes is 5336 in base64, so two chunks
+	var buf bytes.Buffer
+	if err := EncodeKitty(&buf, make([]byte, 4000)); err != nil {
+		t.Fatal(err)
+	}
+
+	escapes := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\x1b\\")
+	if len(escapes) != 3 || escapes[2] != "" {
+		t.Fatalf("expected two escapes, got %q", buf.String())
+	}
+	if !strings.HasPrefix(escapes[0], "\x1b_Gf=100,a=T,m=1;") {
+		t.Errorf("first chunk starts %q", escapes[0][:20])
+	}
+	if !strings.HasPrefix(escapes[1], "\x1b_Gm=0;") {
+		t.Errorf("last chunk starts %q", escapes[1][:20])
+	}
+}
+
+func TestDetectProtocol(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		da1      string
+		expected Protocol
+	}{
+		{env: map[string]string{"TERM": "xterm-kitty"}, expected: Kitty},
+		{env: map[string]string{"KITTY_WINDOW_ID": "1", "TERM": "xterm-256color"}, expected: Kitty},
+		{env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, expected: ITerm2},
+		{env: map[string]string{"TERM": "foot"}, da1: "\x1b[?62;4;22c", expected: Sixel},
+		{env: map[string]string{"TERM": "xterm-256color"}, da1: "\x1b[?1;2c", expected: Blocks},
+		{env: map[string]string{"TERM": "xterm-256color"}, da1: "\x1b[?64;14;22c", expected: Blocks},
+	}
+
+	for _, c := range cases {
+		da1 := func() (string, error) { return c.da1, nil }
+		actual := DetectProtocol(func(key string) string { return c.env[key] }, da1)
+		if actual != c.expected {
+			t.Errorf("protocol for %v and %q is %v; want %v", c.env, c.da1, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package sprite draws Pokemon sprites in the terminal.
+package sprite
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+// pixels less opaque than this are drawn as background
+const alphaThreshold = 0x8000
+
+// from darkest to lightest, for terminals without color
+const asciiRamp = " .:-=+*#%@"
+
+func Decode(data []byte) (image.Image, error) {
+	return png.Decode(bytes.NewReader(data))
+}
+
+// Render draws img to w, two pixels per character cell using half blocks,
+// or as ASCII art without color. Transparent padding around the sprite is
+// trimmed first.
+func Render(w io.Writer, img image.Image, mode ansi.ColorMode) error {
+	bw := bufio.NewWriter(w)
+	bounds := trim(img)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.At(x, y)
+			bottom := color.Color(color.Transparent)
+			if y+1 < bounds.Max.Y {
+				bottom = img.At(x, y+1)
+			}
+			if mode == ansi.NoColor {
+				bw.WriteByte(asciiCell(top, bottom))
+			} else {
+				bw.WriteString(halfBlockCell(top, bottom, mode))
+			}
+		}
+		if mode != ansi.NoColor {
+			bw.WriteString(ansi.Reset)
+		}
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+// trim returns the smallest rectangle holding every visible pixel of img.
+func trim(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X, b.Min.Y
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if visible(img.At(x, y)) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x+1), max(maxY, y+1)
+			}
+		}
+	}
+	if minX >= maxX {
+		return image.Rectangle{}
+	}
+	return image.Rect(minX, minY, maxX, maxY)
+}
+
+func visible(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= alphaThreshold
+}
+
+func halfBlockCell(top, bottom color.Color, mode ansi.ColorMode) string {
+	switch {
+	case visible(top) && visible(bottom):
+		return fg(top, mode) + bg(bottom, mode) + "▀" + ansi.Reset
+	case visible(top):
+		return fg(top, mode) + "▀" + ansi.Reset
+	case visible(bottom):
+		return fg(bottom, mode) + "▄" + ansi.Reset
+	default:
+		return " "
+	}
+}
+
+func fg(c color.Color, mode ansi.ColorMode) string {
+	r, g, b := rgb(c)
+	return ansi.Fg(r, g, b, mode)
+}
+
+func bg(c color.Color, mode ansi.ColorMode) string {
+	r, g, b := rgb(c)
+	return ansi.Bg(r, g, b, mode)
+}
+
+func rgb(c color.Color) (uint8, uint8, uint8) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return n.R, n.G, n.B
+}
+
+func asciiCell(top, bottom color.Color) byte {
+	total, count := 0.0, 0
+	for _, c := range []color.Color{top, bottom} {
+		if visible(c) {
+			total += luminance(c)
+			count++
+		}
+	}
+	if count == 0 {
+		return ' '
+	}
+	// darker pixels get denser characters, so they stand out on a light
+	// sprite's outline like they do in color
+	i := int((1 - total/float64(count)) * float64(len(asciiRamp)-1))
+	return asciiRamp[max(i, 1)]
+}
+
+func luminance(c color.Color) float64 {
+	r, g, b := rgb(c)
+	return (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / 255
+}
@@ -0,0 +1,73 @@
+package sprite
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+// testImage is a 3x3 image with a transparent border around a 2x2 square:
+// red and green on top, blue and black below.
+func testImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 3))
+	img.Set(1, 1, color.NRGBA{255, 0, 0, 255})
+	img.Set(2, 1, color.NRGBA{0, 255, 0, 255})
+	img.Set(1, 2, color.NRGBA{0, 0, 255, 255})
+	img.Set(2, 2, color.NRGBA{0, 0, 0, 255})
+	return img
+}
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		mode     ansi.ColorMode
+		expected string
+	}{
+		{
+			mode: ansi.TrueColor,
+			expected: "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m" +
+				"\x1b[38;2;0;255;0m\x1b[48;2;0;0;0m▀\x1b[0m\x1b[0m\n",
+		},
+		{
+			mode: ansi.Color256,
+			expected: "\x1b[38;5;196m\x1b[48;5;21m▀\x1b[0m" +
+				"\x1b[38;5;46m\x1b[48;5;16m▀\x1b[0m\x1b[0m\n",
+		},
+		{
+			mode:     ansi.NoColor,
+			expected: "#+\n",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, testImage(), c.mode); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != c.expected {
+				t.Errorf("rendered %q; want %q", buf.String(), c.expected)
+			}
+		})
+	}
+}
+
+func TestRenderHalfTransparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{255, 255, 255, 255})
+	img.Set(1, 1, color.NRGBA{255, 255, 255, 255})
+
+	var buf bytes.Buffer
+	if err := Render(&buf, img, ansi.TrueColor); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\x1b[38;2;255;255;255m▀\x1b[0m" +
+		"\x1b[38;2;255;255;255m▄\x1b[0m\x1b[0m\n"
+	if buf.String() != expected {
+		t.Errorf("rendered %q; want %q", buf.String(), expected)
+	}
+}
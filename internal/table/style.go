@@ -0,0 +1,67 @@
+package table
+
+import (
+	"strconv"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+// Style is how text is decorated for the terminal it's written to.
+type Style struct {
+	Color ansi.ColorMode
+	// how many cells wide the terminal is, or 0 when it isn't one
+	Width int
+}
+
+// the colors of each type, as the games and most Pokedexes show them
+var typeColors = map[string]string{
+	"normal":   "#A8A77A",
+	"fire":     "#EE8130",
+	"water":    "#6390F0",
+	"electric": "#F7D02C",
+	"grass":    "#7AC74C",
+	"ice":      "#96D9D6",
+	"fighting": "#C22E28",
+	"poison":   "#A33EA1",
+	"ground":   "#E2BF65",
+	"flying":   "#A98FF3",
+	"psychic":  "#F95587",
+	"bug":      "#A6B91A",
+	"rock":     "#B6A136",
+	"ghost":    "#735797",
+	"dragon":   "#6F35FC",
+	"dark":     "#705746",
+	"steel":    "#B7B7CE",
+	"fairy":    "#D685AD",
+}
+
+// Type returns the name of a Pokemon type in its color.
+func (s Style) Type(name string) string {
+	hex, ok := typeColors[name]
+	if !ok {
+		return name
+	}
+	return s.Paint(name, hex)
+}
+
+// Paint colors text with a color like "#F7D02C".
+func (s Style) Paint(text, hex string) string {
+	if s.Color == ansi.NoColor || text == "" {
+		return text
+	}
+	if len(hex) != 7 || hex[0] != '#' {
+		return text
+	}
+	n, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return text
+	}
+	return ansi.Fg(uint8(n>>16), uint8(n>>8), uint8(n), s.Color) + text + ansi.Reset
+}
+
+func (s Style) Bold(text string) string {
+	if s.Color == ansi.NoColor || text == "" {
+		return text
+	}
+	return "\x1b[1m" + text + ansi.Reset
+}
@@ -0,0 +1,244 @@
+// Package table lays out text for the terminal: tables with aligned
+// columns, lists spread across the terminal's width, bar charts, and
+// Pokemon types in their colors.
+package table
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+type Align int
+
+const (
+	Left Align = iota
+	Right
+)
+
+// Column describes one of a table's columns. A column that can shrink is
+// cut short, with an ellipsis, when the table is too wide for the terminal.
+type Column struct {
+	Header    string
+	Align     Align
+	CanShrink bool
+}
+
+// the space between columns
+const gap = "  "
+
+// Table is rows of cells lined up in columns.
+type Table struct {
+	Columns []Column
+	// how far each line is indented
+	Indent int
+
+	rows [][]string
+}
+
+func New(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// AddRow adds a row of cells, one for each column. Cells can be colored
+// with the Style they're rendered with.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render writes the table, with a header line if any column has a header,
+// shrinking the columns that can shrink to fit style.Width.
+func (t *Table) Render(w io.Writer, style Style) error {
+	widths := make([]int, len(t.Columns))
+	hasHeader := false
+	for i, column := range t.Columns {
+		widths[i] = Width(column.Header)
+		hasHeader = hasHeader || column.Header != ""
+	}
+	for _, row := range t.rows {
+		for i := range t.Columns {
+			if i < len(row) {
+				widths[i] = max(widths[i], Width(row[i]))
+			}
+		}
+	}
+	t.fit(widths, style.Width)
+
+	var b strings.Builder
+	if hasHeader {
+		headers := []string{}
+		for _, column := range t.Columns {
+			headers = append(headers, column.Header)
+		}
+		t.writeLine(&b, headers, widths, style.Bold)
+	}
+	for _, row := range t.rows {
+		t.writeLine(&b, row, widths, func(s string) string { return s })
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fit takes the columns that can shrink down, widest first, until the
+// table is no wider than width, or they can't shrink any more.
+func (t *Table) fit(widths []int, width int) {
+	if width <= 0 {
+		return
+	}
+	total := t.Indent + len(gap)*(len(widths)-1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > width {
+		widest := -1
+		for i, column := range t.Columns {
+			if column.CanShrink && widths[i] > 1 && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+func (t *Table) writeLine(b *strings.Builder, cells []string, widths []int, decorate func(string) string) {
+	line := strings.Repeat(" ", t.Indent)
+	for i, column := range t.Columns {
+		cell := ""
+		if i < len(cells) {
+			cell = Truncate(cells[i], widths[i])
+		}
+		padding := strings.Repeat(" ", widths[i]-Width(cell))
+
+		if i > 0 {
+			line += gap
+		}
+		switch {
+		case column.Align == Right:
+			line += padding + decorate(cell)
+		case i == len(t.Columns)-1:
+			// no trailing spaces after the last column
+			line += decorate(cell)
+		default:
+			line += decorate(cell) + padding
+		}
+	}
+	b.WriteString(strings.TrimRight(line, " ") + "\n")
+}
+
+// Grid writes items in as many columns as fit in style.Width, reading down
+// each column in turn like ls does, or one to a line with no width to fit.
+func Grid(w io.Writer, items []string, style Style, indent int) error {
+	cellWidth := 0
+	for _, item := range items {
+		cellWidth = max(cellWidth, Width(item))
+	}
+
+	columns := 1
+	if style.Width > 0 && cellWidth > 0 {
+		columns = max((style.Width-indent+len(gap))/(cellWidth+len(gap)), 1)
+	}
+	rows := (len(items) + columns - 1) / columns
+
+	t := &Table{Indent: indent}
+	for range min(columns, len(items)) {
+		t.Columns = append(t.Columns, Column{})
+	}
+	for r := range rows {
+		cells := []string{}
+		for c := range columns {
+			if i := c*rows + r; i < len(items) {
+				cells = append(cells, items[i])
+			}
+		}
+		t.AddRow(cells...)
+	}
+	return t.Render(w, Style{Color: style.Color})
+}
+
+// Bar draws value out of total as a bar width cells long at most, to an
+// eighth of a cell.
+func Bar(value, total, width int) string {
+	if total <= 0 || value <= 0 {
+		return ""
+	}
+	eighths := min(value, total) * width * 8 / total
+	bar := strings.Repeat("█", eighths/8)
+	if partial := eighths % 8; partial > 0 {
+		bar += string([]rune("▏▎▍▌▋▊▉")[partial-1])
+	}
+	return bar
+}
+
+// Width returns how many cells s takes up on the terminal, leaving out
+// color escape sequences.
+func Width(s string) int {
+	return utf8.RuneCountInString(stripEscapes(s))
+}
+
+// Truncate cuts s short to width cells, ending it with an ellipsis, and
+// keeps its colors.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	cells := 0
+	colored := false
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			colored = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if cells == width-1 {
+			break
+		}
+		b.WriteRune(r)
+		cells++
+		i += size
+	}
+	b.WriteString("…")
+	if colored {
+		b.WriteString(ansi.Reset)
+	}
+	return b.String()
+}
+
+func stripEscapes(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// escapeLen returns the length of the color escape sequence s starts
+// with, or 0 if it doesn't start with one.
+func escapeLen(s string) int {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return 0
+	}
+	end := strings.IndexByte(s, 'm')
+	if end < 0 {
+		return 0
+	}
+	return end + 1
+}
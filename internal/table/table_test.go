@@ -0,0 +1,157 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		columns  []Column
+		rows     [][]string
+		width    int
+		expected string
+	}{
+		{
+			columns: []Column{{Header: "Stat"}, {Header: "Base", Align: Right}},
+			rows:    [][]string{{"hp", "45"}, {"special-attack", "100"}},
+			expected: `Stat            Base
+hp                45
+special-attack   100
+`,
+		},
+		{
+			columns: []Column{{Align: Right}, {CanShrink: true}, {}},
+			rows:    [][]string{{"#025", "pikachu", "caught"}, {"#122", "mr-mime", "seen"}},
+			width:   19,
+			expected: `#025  pika…  caught
+#122  mr-m…  seen
+`,
+		},
+		{
+			columns:  []Column{{}, {}},
+			rows:     [][]string{{"a"}, {"bb", "c"}},
+			expected: "a\nbb  c\n",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			table := New(c.columns...)
+			for _, row := range c.rows {
+				table.AddRow(row...)
+			}
+			var b strings.Builder
+			if err := table.Render(&b, Style{Width: c.width}); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	items := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	cases := []struct {
+		width    int
+		expected string
+	}{
+		{width: 0, expected: "bulbasaur\nivysaur\nvenusaur\ncharmander\ncharmeleon\n"},
+		{width: 40, expected: `bulbasaur  venusaur    charmeleon
+ivysaur    charmander
+`},
+		{width: 5, expected: "bulbasaur\nivysaur\nvenusaur\ncharmander\ncharmeleon\n"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			var b strings.Builder
+			if err := Grid(&b, items, Style{Width: c.width}, 0); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestBar(t *testing.T) {
+	cases := []struct {
+		value, total, width int
+		expected            string
+	}{
+		{value: 0, total: 255, width: 8, expected: ""},
+		{value: 255, total: 255, width: 4, expected: "████"},
+		{value: 5, total: 10, width: 3, expected: "█▌"},
+		{value: 20, total: 10, width: 2, expected: "██"},
+		{value: 1, total: 0, width: 2, expected: ""},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := Bar(c.value, c.total, c.width); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	fire := Style{Color: ansi.TrueColor}.Type("fire")
+	cases := []struct {
+		input    string
+		width    int
+		expected string
+	}{
+		{input: "pikachu", width: 7, expected: "pikachu"},
+		{input: "pikachu", width: 5, expected: "pika…"},
+		{input: fire, width: 4, expected: fire},
+		{input: fire, width: 3, expected: "\x1b[38;2;238;129;48mfi…" + ansi.Reset},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual := Truncate(c.input, c.width)
+			if actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+			if Width(actual) > c.width {
+				t.Errorf("expected at most %d cells, got %d", c.width, Width(actual))
+			}
+		})
+	}
+}
+
+func TestPaint(t *testing.T) {
+	cases := []struct {
+		style    Style
+		expected string
+	}{
+		{style: Style{}, expected: "water"},
+		{style: Style{Color: ansi.TrueColor}, expected: "\x1b[38;2;99;144;240mwater" + ansi.Reset},
+		{style: Style{Color: ansi.Color256}, expected: "\x1b[38;5;111mwater" + ansi.Reset},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := c.style.Type("water"); actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPaintBadColor(t *testing.T) {
+	style := Style{Color: ansi.TrueColor}
+	for _, hex := range []string{"", "#", "F7D02C", "#F7D02", "F7D02C0", "#GGGGGG"} {
+		if actual := style.Paint("text", hex); actual != "text" {
+			t.Errorf("painting with %q gave %q", hex, actual)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package term controls the terminal the CLI runs in, for the features that
+// need more than a line-buffered stream: raw input and querying the terminal.
+package term
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+var ErrUnsupported = errors.New("terminal control isn't supported on this platform")
+
+// Query writes query to the terminal on out and returns its reply from in,
+// up to and including the end byte. Terminals that don't understand the
+// query don't reply, so reading gives up after timeout.
+func Query(in, out *os.File, query string, end byte, timeout time.Duration) (string, error) {
+	fd := int(in.Fd())
+	state, err := makeRaw(fd, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer Restore(fd, state)
+
+	if _, err := out.WriteString(query); err != nil {
+		return "", err
+	}
+
+	reply := []byte{}
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n == 0 || err != nil {
+			return string(reply), errors.New("no reply from the terminal")
+		}
+		reply = append(reply, buf[0])
+		if buf[0] == end {
+			return string(reply), nil
+		}
+	}
+}
+
+// MakeRaw puts the terminal into raw mode, where input isn't echoed and is
+// read a byte at a time, and returns the previous state for Restore.
+func MakeRaw(fd int) (*State, error) {
+	return makeRaw(fd, 0)
+}
@@ -0,0 +1,9 @@
+package term
+
+import "syscall"
+
+const (
+	ioctlReadTermios  = syscall.TCGETS
+	ioctlWriteTermios = syscall.TCSETS
+	ioctlGetWinsize   = syscall.TIOCGWINSZ
+)
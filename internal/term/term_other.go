@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package term
+
+import "time"
+
+type State struct{}
+
+func IsTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int, timeout time.Duration) (*State, error) {
+	return nil, ErrUnsupported
+}
+
+func Restore(fd int, state *State) error {
+	return ErrUnsupported
+}
+
+func Size(fd int) (width, height int, err error) {
+	return 0, 0, ErrUnsupported
+}
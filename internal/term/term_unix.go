@@ -0,0 +1,80 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package term
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+type State struct {
+	termios syscall.Termios
+}
+
+func IsTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// makeRaw sets raw mode. With a timeout, reads return empty-handed once no
+// byte arrives within it, to a resolution of a tenth of a second.
+func makeRaw(fd int, timeout time.Duration) (*State, error) {
+	termios, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	state := State{termios: *termios}
+
+	raw := *termios
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if timeout > 0 {
+		raw.Cc[syscall.VMIN] = 0
+		raw.Cc[syscall.VTIME] = uint8(min(max(timeout/(100*time.Millisecond), 1), 255))
+	}
+
+	if err := setTermios(fd, &raw); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func Restore(fd int, state *State) error {
+	return setTermios(fd, &state.termios)
+}
+
+// Size returns the width and height of the terminal, in character cells.
+func Size(fd int) (width, height int, err error) {
+	var winsize struct {
+		rows, cols, xPixels, yPixels uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlGetWinsize, uintptr(unsafe.Pointer(&winsize)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(winsize.cols), int(winsize.rows), nil
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlReadTermios, uintptr(unsafe.Pointer(&termios)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &termios, nil
+}
+
+func setTermios(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlWriteTermios, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
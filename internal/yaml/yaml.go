@@ -0,0 +1,265 @@
+// Package yaml writes values as YAML, in block style. Like encoding/json,
+// struct fields are named by their json tags, which can leave them out
+// with "-" or when empty with omitempty, and values that marshal themselves
+// to text, like time.Time, are written as that text.
+package yaml
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Marshal returns the YAML for v.
+func Marshal(v any) ([]byte, error) {
+	var b bytes.Buffer
+	e := encoder{&b}
+	if err := e.document(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+type encoder struct {
+	b *bytes.Buffer
+}
+
+// an entry of a mapping, from a struct field or a map key
+type entry struct {
+	key   string
+	value reflect.Value
+}
+
+func (e encoder) document(v reflect.Value) error {
+	v = indirect(v)
+	if s, ok, err := scalar(v); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		e.b.WriteString(s + "\n")
+		return nil
+	}
+	if empty, s := emptyCollection(v); empty {
+		e.b.WriteString(s + "\n")
+		return nil
+	}
+	return e.block(v, 0, false)
+}
+
+// block writes a mapping or sequence with its lines at indent. When inline,
+// the first line continues the current one, as after "- ".
+func (e encoder) block(v reflect.Value, indent int, inline bool) error {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			e.pad(indent, inline && i == 0)
+			e.b.WriteString("-")
+			if err := e.child(v.Index(i), indent, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	entries, err := mapping(v)
+	if err != nil {
+		return err
+	}
+	for i, en := range entries {
+		e.pad(indent, inline && i == 0)
+		e.b.WriteString(quote(en.key) + ":")
+		if err := e.child(en.value, indent, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// child writes the value after a key or "-": a scalar on the same line, or
+// a block below it, though a sequence item's block starts on its line.
+func (e encoder) child(v reflect.Value, indent int, item bool) error {
+	v = indirect(v)
+	s, ok, err := scalar(v)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		var empty bool
+		empty, s = emptyCollection(v)
+		ok = empty
+	}
+	if ok {
+		e.b.WriteString(" " + s + "\n")
+		return nil
+	}
+
+	if item {
+		e.b.WriteString(" ")
+		return e.block(v, indent+2, true)
+	}
+	e.b.WriteString("\n")
+	return e.block(v, indent+2, false)
+}
+
+func (e encoder) pad(indent int, inline bool) {
+	if !inline {
+		e.b.WriteString(strings.Repeat(" ", indent))
+	}
+}
+
+// indirect follows pointers and interfaces to the value they hold.
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if _, ok := v.Interface().(encoding.TextMarshaler); ok && v.Kind() == reflect.Pointer {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// scalar returns v written as a scalar, if it's one.
+func scalar(v reflect.Value) (string, bool, error) {
+	if !v.IsValid() {
+		return "null", true, nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface ||
+		v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return "null", true, nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", false, err
+		}
+		return quote(string(text)), true, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true, nil
+	case reflect.String:
+		return quote(v.String()), true, nil
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("yaml: can't write a %s", v.Type())
+	}
+}
+
+// emptyCollection reports whether v is an empty mapping or sequence, which
+// are written in flow style as {} or [].
+func emptyCollection(v reflect.Value) (bool, string) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.Len() == 0, "[]"
+	case reflect.Map:
+		return v.Len() == 0, "{}"
+	case reflect.Struct:
+		entries, err := mapping(v)
+		return err == nil && len(entries) == 0, "{}"
+	}
+	return false, ""
+}
+
+// mapping returns the entries of a struct or map, with map keys sorted.
+func mapping(v reflect.Value) ([]entry, error) {
+	if v.Kind() == reflect.Map {
+		entries := []entry{}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			if m, ok := key.Interface().(encoding.TextMarshaler); ok {
+				text, err := m.MarshalText()
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, entry{string(text), iter.Value()})
+			} else {
+				entries = append(entries, entry{fmt.Sprint(key.Interface()), iter.Value()})
+			}
+		}
+		slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+		return entries, nil
+	}
+
+	entries := []entry{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		value := v.Field(i)
+
+		// the fields of embedded structs are written as if they were v's,
+		// even when the struct's type is unexported, as encoding/json does
+		embedded := field.Anonymous && name == "" &&
+			(field.IsExported() || field.Type.Kind() == reflect.Struct)
+		if !field.IsExported() && !embedded {
+			continue
+		}
+		if embedded && indirect(value).Kind() == reflect.Struct {
+			embedded, err := mapping(indirect(value))
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, embedded...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if slices.Contains(strings.Split(opts, ","), "omitempty") && isEmpty(value) {
+			continue
+		}
+		entries = append(entries, entry{name, value})
+	}
+	return entries, nil
+}
+
+// isEmpty reports whether omitempty leaves v out, as encoding/json does.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+var (
+	// strings that read as something other than a string unquoted
+	reserved = regexp.MustCompile(`^(?i:~|null|true|false|yes|no|on|off|y|n|[-+]?(\.inf|\.nan))$`)
+	number   = regexp.MustCompile(`^[-+]?(\d[\d_]*)?\.?\d*([eE][-+]?\d+)?$|^0[xo][0-9a-fA-F]+$`)
+)
+
+// quote returns s as a plain scalar when that reads back as the same
+// string, and double-quoted when it doesn't.
+func quote(s string) string {
+	if s == "" || reserved.MatchString(s) || number.MatchString(s) ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@` \t") ||
+		strings.HasSuffix(s, " ") || strings.HasSuffix(s, ":") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.ContainsFunc(s, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return strconv.Quote(s)
+	}
+	return s
+}
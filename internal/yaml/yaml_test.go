@@ -0,0 +1,117 @@
+package yaml
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type stat struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+type position struct {
+	Region string `json:"region"`
+}
+
+type travel struct {
+	position
+	At string `json:"at"`
+}
+
+type pokemon struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Nickname string            `json:"nickname,omitempty"`
+	Types    []string          `json:"types"`
+	Stats    []stat            `json:"stats"`
+	Moves    []string          `json:"moves"`
+	Extra    map[string]string `json:"extra"`
+	Caught   time.Time         `json:"caught"`
+	Secret   string            `json:"-"`
+	owner    string
+}
+
+func TestMarshal(t *testing.T) {
+	cases := []struct {
+		input    any
+		expected string
+	}{
+		{input: "pikachu", expected: "pikachu\n"},
+		{input: 25, expected: "25\n"},
+		{input: nil, expected: "null\n"},
+		{input: []string{}, expected: "[]\n"},
+		{input: []any{"a", 1, true, nil}, expected: "- a\n- 1\n- true\n- null\n"},
+		{input: map[string]int{"b": 2, "a": 1}, expected: "a: 1\nb: 2\n"},
+		{input: [][]int{{1, 2}, {3}}, expected: "- - 1\n  - 2\n- - 3\n"},
+		{
+			input: pokemon{
+				ID:     25,
+				Name:   "pikachu",
+				Types:  []string{"electric"},
+				Stats:  []stat{{"hp", 35}, {"speed", 90}},
+				Extra:  map[string]string{"cry": "pika: pi", "color": "yellow"},
+				Caught: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+				Secret: "shh",
+				owner:  "ash",
+			},
+			expected: `id: 25
+name: pikachu
+types:
+  - electric
+stats:
+  - name: hp
+    value: 35
+  - name: speed
+    value: 90
+moves: null
+extra:
+  color: yellow
+  cry: "pika: pi"
+caught: 2024-05-01T12:00:00Z
+`,
+		},
+		{input: &stat{"hp", 35}, expected: "name: hp\nvalue: 35\n"},
+		{input: travel{position{"kanto"}, "noon"}, expected: "region: kanto\nat: noon\n"},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			actual, err := Marshal(c.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "mr-mime", expected: "mr-mime"},
+		{input: "Mr. Sparky", expected: "Mr. Sparky"},
+		{input: "", expected: `""`},
+		{input: "yes", expected: `"yes"`},
+		{input: "Null", expected: `"Null"`},
+		{input: "025", expected: `"025"`},
+		{input: "1.5e3", expected: `"1.5e3"`},
+		{input: "-leading", expected: `"-leading"`},
+		{input: "a #comment", expected: `"a #comment"`},
+		{input: "two\nlines", expected: `"two\nlines"`},
+		{input: "trailing ", expected: `"trailing "`},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if actual := quote(c.input); actual != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
@@ -1,206 +1,945 @@
 package main
 
 import (
-	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"math/rand"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/chuckatc/pokedexcli/internal/assets"
+	"github.com/chuckatc/pokedexcli/internal/lineedit"
 	"github.com/chuckatc/pokedexcli/internal/pokeapi"
 	"github.com/chuckatc/pokedexcli/internal/pokecache"
+	"github.com/chuckatc/pokedexcli/internal/sprite"
+	"github.com/chuckatc/pokedexcli/internal/table"
+	"github.com/chuckatc/pokedexcli/internal/term"
 )
 
 type cliCommand struct {
 	name        string
 	description string
-	callback    func(*cmdConfig, []string) error
+	callback    func(*cmdConfig, cmdInput) error
+
+	// what help groups the command under, more about what it does, and
+	// ways to use it
+	category string
+	long     string
+	examples []string
+
+	// the arguments and flags the command takes, which the REPL parses
+	// before calling it
+	args  []argSpec
+	flags []flagSpec
+
+	// complete offers words for the argument after args, if the command
+	// can suggest any
+	complete func(config *cmdConfig, args []string) []string
+
+	// textOnly commands only write text for people, like help, so they
+	// don't take --output
+	textOnly bool
 }
 
 type cmdConfig struct {
 	cache       *pokecache.Cache
 	cmdRegistry map[string]cliCommand
-	pokedex     map[string]pokeapi.PokemonData
+	dataDir     string
+	settings    settings
 	Next        string
 	Previous    string
+
+	// the trainer's own aliases and macros, by name, shared by every profile
+	aliases map[string]string
+	macros  map[string]string
+
+	// the active profile's trainer, and when their current stretch of
+	// play began
+	trainer   trainer
+	playStart time.Time
+	inventory map[string]int
+
+	// every Pokemon the trainer has caught, in their party or the PC
+	party       []*OwnedPokemon
+	boxes       [][]*OwnedPokemon
+	nextOwnedID int
+	pokedex     map[int]dexEntry
+
+	// current position, from broadest to narrowest
+	region       string
+	location     string
+	locationArea string
+	travelLog    []travelLogEntry
+
+	// what's been seen this session, for tab completion
+	lastExplored []string
+
+	// PokeAPI lists of names, by resource, once they've been fetched
+	nameLists map[string][]string
+
+	// the wild Pokemon met by wandering, if it hasn't fled
+	wild *wildEncounter
+	// whether each Pokemon found in the current area some other way is
+	// shiny, settled when it's first found
+	areaShiny map[string]bool
+	battle    *activeBattle
+	rng       *rand.Rand
+
+	// how results are written, one of outputFormats
+	output string
+
+	// where commands and prompts mid-command read from, and the lines
+	// typed before when that's a terminal
+	input   lineReader
+	history *lineedit.History
+
+	// the sprite protocol the terminal supports, once it's been asked
+	detectedProtocol *sprite.Protocol
+
+	// downloaded sprites and cries, or nil if there's nowhere to keep them
+	assets *assets.Library
 }
 
 func main() {
-	cmdRegistry := map[string]cliCommand{
+	commands := flag.String("c", "", "run `commands`, separated by semicolons, then exit")
+	output := flag.String("output", outputFormats[0], "write results as `format`: text, json or yaml")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "usage: pokedexcli [-output format] [-c commands | run <script>]")
+		fmt.Fprintln(out, "\nWith neither, commands are read from stdin: typed at a prompt, or piped in.")
+		fmt.Fprintln(out, "Scripts stop at the first command that fails, exiting with status 1.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if !slices.Contains(outputFormats, *output) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var script io.Reader
+	scriptName := ""
+	switch {
+	case *commands != "" && flag.NArg() == 0:
+		script, scriptName = strings.NewReader(*commands), "-c"
+	case flag.NArg() == 2 && flag.Arg(0) == "run" && *commands == "":
+		f, err := os.Open(flag.Arg(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		script, scriptName = f, flag.Arg(1)
+	case flag.NArg() > 0 || *commands != "":
+		flag.Usage()
+		os.Exit(2)
+	case !term.IsTerminal(int(os.Stdin.Fd())):
+		script, scriptName = os.Stdin, "stdin"
+	}
+
+	var library *assets.Library
+	if dir := defaultAssetDir(); dir != "" {
+		var err error
+		if library, err = assets.Open(dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	config := cmdConfig{
+		cache:       pokecache.NewCache(5 * time.Second),
+		cmdRegistry: newRegistry(),
+		dataDir:     defaultDataDir(),
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		assets:      library,
+		output:      *output,
+	}
+	if err := startSession(&config); err != nil {
+		log.Fatal(err)
+	}
+
+	if script != nil {
+		status := runScript(&config, script, scriptName)
+		os.Exit(status)
+	}
+	repl(config)
+}
+
+// newRegistry returns every command the REPL knows, by name.
+func newRegistry() map[string]cliCommand {
+	return map[string]cliCommand{
+		"version": {
+			name:        "version",
+			description: "Choose the game version to show data from",
+			category:    "data",
+			long:        "Pokemon can differ between game versions: where they're found, their sprites and the moves they learn. Choosing a version shows only its data; all shows every version's.",
+			examples:    []string{"version", "version firered", "version all"},
+			callback:    commandVersion,
+			args: []argSpec{
+				{name: "version_name", kind: nameArg, optional: true, help: "the version to switch to, or all for every version; lists them if left out"},
+			},
+		},
 		"help": {
 			name:        "help",
 			description: "Displays a help message",
+			category:    "system",
+			examples:    []string{"help", "help catch"},
 			callback:    commandHelp,
+			textOnly:    true,
+			complete:    completeHelp,
+			args: []argSpec{
+				{name: "command", kind: nameArg, optional: true, help: "the command to explain"},
+			},
 		},
 		"exit": {
 			name:        "exit",
 			description: "Exit the Pokedex",
+			category:    "system",
+			long:        "Your progress is saved first.",
+			examples:    []string{"exit"},
 			callback:    commandExit,
 		},
 		"map": {
 			name:        "map",
 			description: "Show next map locations",
+			category:    "navigation",
+			long:        "Each use shows the next page of location areas, across every region.",
+			examples:    []string{"map"},
 			callback:    commandMap,
 		},
 		"mapb": {
 			name:        "mapb",
 			description: "Show previous map locations",
+			category:    "navigation",
+			examples:    []string{"mapb"},
 			callback:    commandMapB,
 		},
+		"regions": {
+			name:        "regions",
+			description: "List all regions",
+			category:    "navigation",
+			long:        "The region you're in is marked.",
+			examples:    []string{"regions"},
+			callback:    commandRegions,
+		},
+		"region": {
+			name:        "region",
+			description: "Go to a region and list its locations",
+			category:    "navigation",
+			examples:    []string{"region kanto", "region"},
+			callback:    commandRegion,
+			args: []argSpec{
+				{name: "region_name", kind: nameArg, optional: true, help: "the region to go to; the current one if left out"},
+			},
+		},
+		"location": {
+			name:        "location",
+			description: "List the areas of the current location, or the given one",
+			category:    "navigation",
+			examples:    []string{"location", "location pallet-town"},
+			callback:    commandLocation,
+			args: []argSpec{
+				{name: "location_name", kind: nameArg, optional: true, help: "the location to list the areas of"},
+			},
+		},
+		"travel": {
+			name:        "travel",
+			description: "Travel to a location in the current region",
+			category:    "navigation",
+			long:        "When the location has only one area, you go straight to it.",
+			examples:    []string{"travel viridian-forest"},
+			callback:    commandTravel,
+			args: []argSpec{
+				{name: "location_name", kind: nameArg, help: "a location in the current region"},
+			},
+		},
+		"area": {
+			name:        "area",
+			description: "Go to an area of the current location",
+			category:    "navigation",
+			examples:    []string{"area mt-moon-b1f"},
+			callback:    commandArea,
+			args: []argSpec{
+				{name: "location_area", kind: nameArg, help: "an area of the current location"},
+			},
+		},
+		"travellog": {
+			name:        "travellog",
+			description: "Show where you've been",
+			category:    "navigation",
+			examples:    []string{"travellog"},
+			callback:    commandTravelLog,
+		},
 		"explore": {
 			name:        "explore",
-			description: "Explore a location",
+			description: "Explore the current area, or the given one",
+			category:    "navigation",
+			long:        "Lists the Pokemon that can be found in the area, which marks them as seen in your Pokedex.",
+			examples:    []string{"explore", "explore canalave-city-area"},
 			callback:    commandExplore,
+			complete:    completeExplore,
+			args: []argSpec{
+				{name: "location_area", kind: nameArg, optional: true, help: "an area of the current location"},
+			},
+		},
+		"where": {
+			name:        "where",
+			description: "Show where a Pokemon can be found",
+			category:    "data",
+			long:        "Lists the areas a Pokemon can be encountered in, how, at what levels and how often.",
+			examples:    []string{"where pikachu", "where pikachu --all"},
+			callback:    commandWhere,
+			args: []argSpec{
+				{name: "pokemon_name", kind: nameArg, help: "the Pokemon to look for"},
+			},
+			flags: []flagSpec{
+				{name: "all", help: "show every game version, not just the one chosen with version"},
+			},
+		},
+		"wander": {
+			name:        "wander",
+			description: "Look for a wild Pokemon in the current area",
+			category:    "battle",
+			long:        "Wild Pokemon turn up by the area's encounter methods, at the odds and levels of the games. The wild Pokemon stays until you catch it, battle it or move on.",
+			examples:    []string{"wander", "wander surf"},
+			callback:    commandWander,
+			args: []argSpec{
+				{name: "encounter_method", kind: nameArg, optional: true, help: "how to look, like walk or surf; walking if the area allows it"},
+			},
+		},
+		"battle": {
+			name:        "battle",
+			description: "Battle the wild Pokemon with your party lead, or the given Pokemon",
+			category:    "battle",
+			examples:    []string{"battle", "battle 12"},
+			callback:    commandBattle,
+			complete:    completePartyID,
+			args: []argSpec{
+				{name: "pokemon_id", optional: true, help: "the party Pokemon to send out; your lead if left out"},
+			},
+		},
+		"fight": {
+			name:        "fight",
+			description: "Use a move in battle",
+			category:    "battle",
+			examples:    []string{"fight 1", "fight thunder-shock"},
+			callback:    commandFight,
+			args: []argSpec{
+				{name: "move", kind: nameArg, help: "the move's number in the battle listing, or its name"},
+			},
+		},
+		"run": {
+			name:        "run",
+			description: "Run away from a battle",
+			category:    "battle",
+			examples:    []string{"run"},
+			callback:    commandRun,
+		},
+		"party": {
+			name:        "party",
+			description: "Show the Pokemon in your party",
+			category:    "collection",
+			examples:    []string{"party"},
+			callback:    commandParty,
+		},
+		"pc": {
+			name:        "pc",
+			description: "Show the Pokemon in your PC boxes",
+			category:    "collection",
+			examples:    []string{"pc"},
+			callback:    commandPC,
+		},
+		"deposit": {
+			name:        "deposit",
+			description: "Move a party Pokemon to the PC",
+			category:    "collection",
+			examples:    []string{"deposit 12"},
+			callback:    commandDeposit,
+			complete:    completePartyID,
+			args: []argSpec{
+				{name: "pokemon_id", help: "the party Pokemon to deposit"},
+			},
+		},
+		"withdraw": {
+			name:        "withdraw",
+			description: "Move a Pokemon from the PC to your party",
+			category:    "collection",
+			examples:    []string{"withdraw 12"},
+			callback:    commandWithdraw,
+			complete:    completeBoxID,
+			args: []argSpec{
+				{name: "pokemon_id", help: "the PC Pokemon to withdraw"},
+			},
+		},
+		"swap": {
+			name:        "swap",
+			description: "Swap the places of two of your Pokemon",
+			category:    "collection",
+			long:        "Either Pokemon can be in your party or the PC, so swapping can also move one between them.",
+			examples:    []string{"swap 1 12"},
+			callback:    commandSwap,
+			complete:    completeOwnedID,
+			args: []argSpec{
+				{name: "pokemon_id", help: "one Pokemon, in your party or the PC"},
+				{name: "other_pokemon_id", help: "the Pokemon to trade places with"},
+			},
+		},
+		"release": {
+			name:        "release",
+			description: "Release one of your Pokemon",
+			category:    "collection",
+			examples:    []string{"release 12"},
+			callback:    commandRelease,
+			complete:    completeOwnedID,
+			args: []argSpec{
+				{name: "pokemon_id", help: "the Pokemon to release, in your party or the PC"},
+			},
+		},
+		"settings": {
+			name:        "settings",
+			description: "Show your settings, or change one",
+			category:    "system",
+			examples:    []string{"settings", "settings shiny-odds 512", "settings offline on"},
+			callback:    commandSettings,
+			args: []argSpec{
+				{name: "setting", kind: nameArg, optional: true, choices: settingNames, help: "the setting to change; lists them all if left out"},
+				{name: "value", kind: nameArg, optional: true, help: "the setting's new value"},
+			},
+		},
+		"rename": {
+			name:        "rename",
+			description: "Give one of your Pokemon a nickname",
+			category:    "collection",
+			examples:    []string{"rename 12 Sparky", `rename pikachu "Mr. Sparky"`, "rename 12"},
+			callback:    commandRename,
+			complete:    completeOwned,
+			args: []argSpec{
+				{name: "pokemon_id", help: "the Pokemon to rename, by ID, name or nickname"},
+				{name: "nickname", optional: true, rest: true, help: "its new nickname; removes the nickname if left out"},
+			},
 		},
 		"catch": {
 			name:        "catch",
 			description: "Try to catch a Pokemon",
+			category:    "collection",
+			long:        "A Pokemon with more base experience is harder to catch. Caught Pokemon join your party, or go to the PC once it's full.",
+			examples:    []string{"catch pikachu", "catch vulpix --form alola"},
 			callback:    commandCatch,
+			complete:    completeCatch,
+			args: []argSpec{
+				{name: "pokemon_name", kind: nameArg, help: "the wild Pokemon you're facing, or one found in the current area"},
+			},
+			flags: []flagSpec{
+				{name: "form", value: "form_name", kind: nameArg, help: "catch one of its alternate forms, like alola"},
+			},
 		},
 		"inspect": {
 			name:        "inspect",
 			description: "Inspect a Pokemon",
+			category:    "collection",
+			examples:    []string{"inspect 12", "inspect sparky --sprite"},
 			callback:    commandInspect,
+			complete:    completeOwned,
+			args: []argSpec{
+				{name: "pokemon_id", help: "one of your Pokemon, by ID, name or nickname"},
+			},
+			flags: []flagSpec{
+				{name: "sprite", help: "draw its sprite too"},
+			},
+		},
+		"sprite": {
+			name:        "sprite",
+			description: "Draw a Pokemon's sprite",
+			category:    "data",
+			long:        "Sprites are drawn with the best graphics your terminal supports; see settings sprite-protocol.",
+			examples:    []string{"sprite pikachu", "sprite pikachu --shiny --back", "sprite pikachu --gen 1"},
+			callback:    commandSprite,
+			textOnly:    true,
+			args: []argSpec{
+				{name: "pokemon_name", kind: nameArg, help: "the Pokemon to draw"},
+			},
+			flags: []flagSpec{
+				{name: "shiny", help: "draw its shiny colors"},
+				{name: "back", help: "draw it from behind"},
+				{name: "gen", value: "n", kind: intArg, help: "draw it as it looked in generation n"},
+			},
 		},
 		"pokedex": {
 			name:        "pokedex",
-			description: "Show Pokemon in your Pokedex",
+			description: "Show the Pokemon you've seen and caught",
+			category:    "collection",
+			examples:    []string{"pokedex", "pokedex kanto --missing", "pokedex --stats"},
 			callback:    commandPokedex,
+			args: []argSpec{
+				{name: "pokedex_name", kind: nameArg, optional: true, def: nationalDex, help: "the Pokedex to list, like kanto"},
+			},
+			flags: []flagSpec{
+				{name: "seen", help: "list only the Pokemon you've seen but not caught"},
+				{name: "caught", help: "list only the Pokemon you've caught"},
+				{name: "missing", help: "list only the Pokemon you haven't caught"},
+				{name: "stats", help: "add up your Pokemon by type and generation"},
+			},
+		},
+		"profile": {
+			name:        "profile",
+			description: "Create, list, switch between and delete trainer profiles",
+			category:    "system",
+			long:        "Each trainer has their own Pokemon, Pokedex and settings. Deleting a profile asks first.",
+			examples:    []string{"profile", "profile new blue", "profile switch blue"},
+			callback:    commandProfile,
+			textOnly:    true,
+			args: []argSpec{
+				{name: "action", kind: nameArg, optional: true, def: "list", choices: []string{"list", "new", "switch", "delete"}, help: "what to do"},
+				{name: "trainer_name", kind: nameArg, optional: true, help: "the profile to create, switch to or delete"},
+			},
+		},
+		"history": {
+			name:        "history",
+			description: "List the commands you've entered, to rerun with !n",
+			category:    "system",
+			examples:    []string{"history"},
+			callback:    commandHistory,
+			textOnly:    true,
+		},
+		"alias": {
+			name:        "alias",
+			description: "List aliases, or give a command a shorter name",
+			category:    "system",
+			long:        "The words after an alias go after what it stands for, so with alias ll=\"pokedex --caught\", ll kanto runs pokedex --caught kanto. Quote the expansion when it has flags or spaces. m, e, c and q are built in, for map, explore, catch and exit.",
+			examples:    []string{"alias", "alias w=where", `alias ll="pokedex --caught"`, "alias --remove ll"},
+			callback:    commandAlias,
+			textOnly:    true,
+			args: []argSpec{
+				{name: "definition", optional: true, rest: true, help: "the alias and what it stands for, like name=expansion"},
+			},
+			flags: []flagSpec{
+				{name: "remove", value: "alias_name", kind: nameArg, help: "remove one of your aliases"},
+			},
+		},
+		"macro": {
+			name:        "macro",
+			description: "List macros, or define one that runs several commands",
+			category:    "system",
+			long:        "A macro's body can run several commands, separated by semicolons, and takes arguments in place of $1 through $9. Quote the body so its semicolons aren't run right away.",
+			examples:    []string{"macro", `macro hunt "travel $1; explore"`, "hunt viridian-forest", "macro hunt", "macro --remove hunt"},
+			callback:    commandMacro,
+			textOnly:    true,
+			args: []argSpec{
+				{name: "macro_name", kind: nameArg, optional: true, help: "the macro to show or define"},
+				{name: "body", optional: true, rest: true, help: "the commands it runs"},
+			},
+			flags: []flagSpec{
+				{name: "remove", value: "macro_name", kind: nameArg, help: "remove a macro"},
+			},
+		},
+		"assets": {
+			name:        "assets",
+			description: "Download sprites and cries to use offline",
+			category:    "data",
+			long:        "Downloaded files are used instead of fetching them, and are all there is to draw from with settings offline on. Stopped downloads pick up where they left off.",
+			examples:    []string{"assets", "assets download pikachu", "assets download --caught", "assets verify"},
+			callback:    commandAssets,
+			textOnly:    true,
+			args: []argSpec{
+				{name: "action", kind: nameArg, optional: true, choices: []string{"download", "verify"}, help: "download files, or check the ones downloaded; counts them if left out"},
+				{name: "pokemon_name", kind: nameArg, optional: true, help: "the Pokemon to download the files of"},
+			},
+			flags: []flagSpec{
+				{name: "caught", help: "download the files of every Pokemon you've caught"},
+				{name: "all", help: "download the files of every Pokemon"},
+			},
 		},
 	}
-
-	config := cmdConfig{
-		cache:       pokecache.NewCache(5 * time.Second),
-		cmdRegistry: cmdRegistry,
-		pokedex:     make(map[string]pokeapi.PokemonData),
-	}
-
-	repl(config)
 }
 
 func repl(config cmdConfig) {
-	scanner := bufio.NewScanner(os.Stdin)
+	config.input = newInput(&config)
 
 	for {
-		fmt.Print("Pokedex > ")
-		if !scanner.Scan() {
+		prompt := "Pokedex > "
+		if config.trainer.Name != "" {
+			prompt = fmt.Sprintf("Pokedex (%s) > ", config.trainer.Name)
+		}
+		input, err := config.input.ReadLine(prompt)
+		if errors.Is(err, lineedit.ErrInterrupt) {
+			continue
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintln(os.Stderr, err)
+			}
 			break
 		}
-		if err := scanner.Err(); err != nil {
-			log.Fatal(err)
+
+		if config.history != nil {
+			expanded, err := config.history.Expand(input)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			if expanded != input {
+				fmt.Println(expanded)
+				input = expanded
+			}
+			if err := config.history.Add(input); err != nil {
+				fmt.Fprintln(os.Stderr, "can't save history:", err)
+			}
 		}
-		input := scanner.Text()
 
-		words := cleanInput(input)
-		if len(words) == 0 {
+		commands, err := cleanInput(input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
 			continue
 		}
-		command := words[0]
-
-		args := []string{}
-		if len(words) > 1 {
-			args = words[1:]
+		for _, words := range commands {
+			runCommand(&config, words)
 		}
+	}
 
-		cliCmd, ok := config.cmdRegistry[command]
-		if !ok {
-			fmt.Println("Unknown command")
-			continue
+	// count the time played since the last save
+	if err := config.save(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// cleanInput splits a line into commands at semicolons, and each command
+// into words, much like a shell does. Single or double quotes keep text
+// together as one word, spaces and semicolons included, and a backslash
+// escapes the character after it (only " and \ within double quotes, and
+// nothing within single quotes). An option written --flag=value is split
+// into --flag and value. Only command names are lowercased, so commands
+// lowercase the names they look up themselves.
+func cleanInput(text string) ([][]string, error) {
+	commands := [][]string{}
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
 		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			words[0] = strings.ToLower(words[0])
+			commands = append(commands, words)
+		}
+		words = []string{}
+	}
 
-		err := cliCmd.callback(&config, args)
-		if err != nil {
-			fmt.Println(err)
+	for _, r := range text {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ';':
+			endCommand()
+		case unicode.IsSpace(r):
+			endWord()
+		case r == '=' && strings.HasPrefix(word.String(), "--"):
+			// the value is a word of its own, even if it's empty
+			endWord()
+			inWord = true
+		default:
+			word.WriteRune(r)
+			inWord = true
 		}
 	}
-}
 
-func cleanInput(text string) []string {
-	textLower := strings.ToLower(text)
-	words := strings.Fields(textLower)
-	return words
+	if quote != 0 {
+		return nil, fmt.Errorf("there's no closing %c", quote)
+	}
+	if escaped {
+		return nil, errors.New("there's nothing after the \\ to escape")
+	}
+	endCommand()
+
+	return commands, nil
 }
 
-func commandHelp(config *cmdConfig, args []string) error {
-	fmt.Print("Welcome to the Pokedex!\nUsage:\n\n")
-	for _, command := range config.cmdRegistry {
-		fmt.Printf("%s: %s\n", command.name, command.description)
+// prompt asks the trainer a question mid-command and returns their answer,
+// or false when there's no one to ask.
+func prompt(config *cmdConfig, question string) (string, bool) {
+	if config.input == nil {
+		return "", false
 	}
-	return nil
+
+	answer, err := config.input.ReadLine(question)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(answer), true
 }
 
-func commandExit(config *cmdConfig, args []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
+func commandExit(config *cmdConfig, in cmdInput) error {
+	if err := config.save(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if config.textOutput() {
+		fmt.Println("Closing the Pokedex... Goodbye!")
+	}
 	os.Exit(0)
 	return nil
 }
 
-func commandMap(config *cmdConfig, args []string) error {
-	mapData := pokeapi.GetMap(config.Next, config.cache)
-	config.Next = mapData.Next
-	config.Previous = mapData.Previous
-	for _, result := range mapData.Results {
-		fmt.Println(result.Name)
-	}
-	return nil
+// areaPage is a page of location areas, as map and mapb show them.
+type areaPage struct {
+	Areas []string `json:"areas"`
 }
 
-func commandMapB(config *cmdConfig, args []string) error {
-	mapData := pokeapi.GetMap(config.Previous, config.cache)
+func (r areaPage) printText(style table.Style) {
+	table.Grid(os.Stdout, r.Areas, style, 0)
+}
+
+func commandMap(config *cmdConfig, in cmdInput) error {
+	return showAreaPage(config, config.Next)
+}
+
+func commandMapB(config *cmdConfig, in cmdInput) error {
+	return showAreaPage(config, config.Previous)
+}
+
+func showAreaPage(config *cmdConfig, url string) error {
+	mapData, err := pokeapi.GetMap(url, config.cache)
+	if err != nil {
+		return err
+	}
 	config.Next = mapData.Next
 	config.Previous = mapData.Previous
+
+	page := areaPage{Areas: []string{}}
 	for _, result := range mapData.Results {
-		fmt.Println(result.Name)
+		page.Areas = append(page.Areas, result.Name)
 	}
-	return nil
+	return writeResult(config, page)
 }
 
-func commandExplore(config *cmdConfig, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: explore <location_area>")
+func commandExplore(config *cmdConfig, in cmdInput) error {
+	locationArea := config.locationArea
+	if in.isSet("location_area") {
+		locationArea = in.get("location_area")
+		if err := checkInCurrentLocation(config, locationArea); err != nil {
+			return err
+		}
+	}
+	if locationArea == "" {
+		return errors.New("you aren't in an area; use travel or area to pick one")
 	}
 
-	exploreData := pokeapi.GetExploreData(args[0], config.cache)
+	exploreData, err := pokeapi.GetExploreData(locationArea, config.cache)
+	if err != nil {
+		return lookupError(config, "location-area", locationArea, err)
+	}
 
-	fmt.Println("Found Pokemon:")
+	config.lastExplored = []string{}
+	config.areaShiny = map[string]bool{}
 	for _, pokeEncounter := range exploreData.PokemonEncounters {
-		fmt.Println("-", pokeEncounter.Pokemon.Name)
+		if hasVersion(config, pokeEncounter) {
+			config.lastExplored = append(config.lastExplored, pokeEncounter.Pokemon.Name)
+			config.areaShiny[pokeEncounter.Pokemon.Name] = rollShiny(config)
+			markSeen(config, pokeEncounter.Pokemon.Name, pokeEncounter.Pokemon.URL)
+		}
 	}
 
-	return nil
+	if err := writeResult(config, exploreResult{Area: locationArea, Pokemon: config.lastExplored}); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// exploreResult is what turned up exploring a location area.
+type exploreResult struct {
+	Area    string   `json:"area"`
+	Pokemon []string `json:"pokemon"`
+}
+
+func (r exploreResult) printText(style table.Style) {
+	fmt.Println("Found Pokemon:")
+	table.Grid(os.Stdout, r.Pokemon, style, 2)
 }
 
-func commandCatch(config *cmdConfig, args []string) error {
-	if len(args) != 1 {
-		return errors.New("usage: catch <pokemon_name>")
+func commandCatch(config *cmdConfig, in cmdInput) error {
+	name := in.get("pokemon_name")
+
+	if err := checkCatchable(config, name); err != nil {
+		return err
+	}
+
+	variety := name
+	if in.isSet("form") {
+		var err error
+		if variety, err = findVariety(config, name, in.get("form")); err != nil {
+			return err
+		}
+	}
+
+	if config.textOutput() {
+		fmt.Printf("Throwing a Pokeball at %s...\n", variety)
+	}
+
+	pokemonData, err := pokeapi.GetPokemonData(variety, config.cache)
+	if err != nil {
+		return fmt.Errorf("you can't get ye %s", variety)
 	}
-	name := args[0]
 
-	fmt.Printf("Throwing a Pokeball at %s...\n", name)
+	// a wild Pokemon's shininess was settled when it appeared
+	var shiny bool
+	if config.wild != nil {
+		shiny = config.wild.shiny
+	} else {
+		shiny = isShinyInArea(config, name)
+	}
+
+	level := defaultCatchLevel
+	if config.wild != nil {
+		level = config.wild.level
+	} else if l, ok := catchLevel(config, name); ok {
+		level = l
+	}
+
+	r := catchResult{Pokemon: name}
+	if !attemptToCatch(config, pokemonData) {
+		if config.wild != nil && config.rng.Float64() < fleeProb {
+			r.Fled = true
+			config.wild = nil
+			config.battle = nil
+		}
+		return writeResult(config, r)
+	}
 
-	pokemonData, err := pokeapi.GetPokemonData(name, config.cache)
+	owned, err := newOwnedPokemon(config, pokemonData, level)
 	if err != nil {
-		return fmt.Errorf("you can't get ye %s", name)
+		return err
+	}
+	owned.Shiny = shiny
+
+	config.wild = nil
+	config.battle = nil
+	// the next one caught here is another Pokemon
+	delete(config.areaShiny, name)
+
+	r.Caught = &ownedSummary{ID: owned.ID, Pokemon: owned.Pokemon, Level: owned.Level}
+	r.Shiny = shiny
+	r.Box = addOwned(config, owned)
+	markCaught(config, owned)
+	if err := writeResult(config, r); err != nil {
+		return err
+	}
+
+	// only a person reading the text can answer
+	if config.textOutput() {
+		question := fmt.Sprintf("Give %s a nickname? (leave blank to skip) ", name)
+		if nickname, ok := prompt(config, question); ok && nickname != "" {
+			if err := setNickname(owned, nickname); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	}
+
+	return config.save()
+}
+
+// catchResult is how a throw went: the Pokemon caught, and where it went,
+// or whether it escaped or fled.
+type catchResult struct {
+	Pokemon string        `json:"pokemon"`
+	Caught  *ownedSummary `json:"caught,omitempty"`
+	Shiny   bool          `json:"shiny,omitempty"`
+	Box     int           `json:"box,omitempty"`
+	Fled    bool          `json:"fled,omitempty"`
+}
+
+func (r catchResult) printText(style table.Style) {
+	switch {
+	case r.Caught == nil:
+		fmt.Println(r.Pokemon, "escaped!")
+		if r.Fled {
+			fmt.Println(r.Pokemon, "fled!")
+		}
+		return
+	case r.Shiny:
+		fmt.Printf("%s was caught, and it's shiny! (ID %d, Lv. %d)\n", r.Pokemon, r.Caught.ID, r.Caught.Level)
+	default:
+		fmt.Printf("%s was caught! (ID %d, Lv. %d)\n", r.Pokemon, r.Caught.ID, r.Caught.Level)
+	}
+	if r.Box > 0 {
+		fmt.Printf("Your party is full, so %s was sent to box %d\n", r.Pokemon, r.Box)
 	}
+}
 
-	if !attemptToCatch(pokemonData) {
-		fmt.Println(name, "escaped!")
+// checkCatchable returns an error unless name can be caught right now: the
+// wild Pokemon being faced if there is one, otherwise any Pokemon found in
+// the current area.
+func checkCatchable(config *cmdConfig, name string) error {
+	if config.wild != nil {
+		if name != config.wild.name {
+			err := fmt.Errorf("you're facing a wild %s", config.wild.name)
+			return unknownName(err, name, func() []string { return []string{config.wild.name} })
+		}
 		return nil
 	}
 
-	fmt.Println(name, "was caught!")
-	config.pokedex[name] = pokemonData
+	if config.locationArea == "" {
+		return errors.New("you aren't in an area; use travel or area to pick one")
+	}
+
+	exploreData, err := pokeapi.GetExploreData(config.locationArea, config.cache)
+	if err != nil {
+		return err
+	}
+	if !encounteredIn(config, exploreData, name) {
+		err := fmt.Errorf("there's no %s in %s", name, config.locationArea)
+		return unknownName(err, name, func() []string {
+			names := []string{}
+			for _, pokeEncounter := range exploreData.PokemonEncounters {
+				if hasVersion(config, pokeEncounter) {
+					names = append(names, pokeEncounter.Pokemon.Name)
+				}
+			}
+			return names
+		})
+	}
 
 	return nil
 }
 
-func attemptToCatch(pokemonData pokeapi.PokemonData) bool {
+func encounteredIn(config *cmdConfig, exploreData pokeapi.LocationAreaDetailData, name string) bool {
+	for _, pokeEncounter := range exploreData.PokemonEncounters {
+		if pokeEncounter.Pokemon.Name == name && hasVersion(config, pokeEncounter) {
+			return true
+		}
+	}
+	return false
+}
+
+func attemptToCatch(config *cmdConfig, pokemonData pokeapi.PokemonData) bool {
 	prob := probToCatch(pokemonData.BaseExperience)
-	randFloat := rand.Float64()
+	randFloat := config.rng.Float64()
 
 	return randFloat > prob
 }
@@ -221,41 +960,187 @@ func probToCatch(baseExp int) float64 {
 	return prob
 }
 
-func commandInspect(config *cmdConfig, args []string) error {
-	if len(args) != 1 {
-		return errors.New("usage: inspect <pokemon_name>")
+func commandInspect(config *cmdConfig, in cmdInput) error {
+	owned, err := findOwned(config, in.get("pokemon_id"))
+	if err != nil {
+		return err
+	}
+	pokemon, err := pokeapi.GetPokemonData(owned.Pokemon, config.cache)
+	if err != nil {
+		return err
+	}
+
+	spriteURL := pokemon.SpriteURL(config.settings.Version, owned.Shiny, false)
+	if in.isSet("sprite") && config.textOutput() {
+		if err := showSprite(config, spriteURL); err != nil {
+			return err
+		}
 	}
-	name := args[0]
 
-	pokemon, ok := config.pokedex[name]
-	if !ok {
-		return fmt.Errorf("you haven't caught %s yet", name)
+	r := inspectResult{
+		ID:          owned.ID,
+		Pokemon:     owned.Pokemon,
+		Nickname:    owned.Nickname,
+		Level:       owned.Level,
+		Experience:  owned.Experience,
+		Form:        owned.Form,
+		Shiny:       owned.Shiny,
+		Nature:      owned.Nature.Name,
+		Height:      pokemon.Height,
+		Weight:      pokemon.Weight,
+		Types:       owned.Types,
+		CaughtAt:    owned.CaughtAt,
+		CaughtIn:    owned.CaughtIn,
+		Ball:        owned.Ball,
+		Sprite:      spriteURL,
+		displayName: owned.DisplayName(),
+	}
+	stats := owned.Stats()
+	for _, stat := range statNames {
+		r.Stats = append(r.Stats, statDetail{
+			Name:  stat,
+			Value: statValue(stats, stat),
+			Base:  statValue(owned.BaseStats, stat),
+			IV:    statValue(owned.IVs, stat),
+			EV:    statValue(owned.EVs, stat),
+		})
+	}
+	if config.settings.Version != "" {
+		r.Version = getVersionDetails(config, pokemon)
 	}
 
-	fmt.Println("Name:", pokemon.Name)
-	fmt.Println("Height:", pokemon.Height)
-	fmt.Println("Weight:", pokemon.Weight)
+	return writeResult(config, r)
+}
 
-	fmt.Println("Stats:")
-	for _, stat := range pokemon.Stats {
-		fmt.Printf("  - %s: %d\n", stat.Stat.Name, stat.BaseStat)
+// inspectResult is all there is to know about one of the trainer's Pokemon.
+type inspectResult struct {
+	ID         int             `json:"id"`
+	Pokemon    string          `json:"pokemon"`
+	Nickname   string          `json:"nickname,omitempty"`
+	Level      int             `json:"level"`
+	Experience int             `json:"experience"`
+	Form       string          `json:"form,omitempty"`
+	Shiny      bool            `json:"shiny"`
+	Nature     string          `json:"nature"`
+	Height     int             `json:"height"`
+	Weight     int             `json:"weight"`
+	Stats      []statDetail    `json:"stats"`
+	Types      []string        `json:"types"`
+	CaughtAt   time.Time       `json:"caught_at"`
+	CaughtIn   string          `json:"caught_in"`
+	Ball       string          `json:"ball"`
+	Sprite     string          `json:"sprite,omitempty"`
+	Version    *versionDetails `json:"version,omitempty"`
+
+	displayName string
+}
+
+type statDetail struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+	Base  int    `json:"base"`
+	IV    int    `json:"iv"`
+	EV    int    `json:"ev"`
+}
+
+// versionDetails are the parts of a Pokemon's data that are specific to
+// the selected game version.
+type versionDetails struct {
+	Version      string        `json:"version"`
+	VersionGroup string        `json:"version_group"`
+	GameIndex    int           `json:"game_index,omitempty"`
+	Moves        []learnedMove `json:"moves"`
+}
+
+type learnedMove struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Level  int    `json:"level,omitempty"`
+}
 
+// the highest a base stat goes, which the bars are drawn out of
+const maxBaseStat = 255
+
+func (r inspectResult) printText(style table.Style) {
+	fmt.Println("ID:", r.ID)
+	fmt.Println("Name:", r.displayName)
+	fmt.Printf("Level: %d (%d exp.)\n", r.Level, r.Experience)
+	if r.Form != "" {
+		fmt.Println("Form:", r.Form)
+	}
+	if r.Shiny {
+		fmt.Println("Shiny: yes")
 	}
+	fmt.Println("Nature:", r.Nature)
+	fmt.Println("Height:", r.Height)
+	fmt.Println("Weight:", r.Weight)
 
-	fmt.Println("Types:")
-	for _, pokeType := range pokemon.Types {
-		fmt.Printf("  - %s\n", pokeType.Type.Name)
+	types := []string{}
+	for _, pokeType := range r.Types {
+		types = append(types, style.Type(pokeType))
 	}
+	fmt.Println("Types:", strings.Join(types, " "))
 
-	return nil
-}
+	fmt.Println("Stats:")
+	stats := table.New(
+		table.Column{Header: "Stat", CanShrink: true},
+		table.Column{Header: "Value", Align: table.Right},
+		table.Column{Header: "Base", Align: table.Right},
+		table.Column{},
+		table.Column{Header: "IV", Align: table.Right},
+		table.Column{Header: "EV", Align: table.Right},
+	)
+	stats.Indent = 2
+	for _, stat := range r.Stats {
+		stats.AddRow(stat.Name, strconv.Itoa(stat.Value), strconv.Itoa(stat.Base),
+			table.Bar(stat.Base, maxBaseStat, 16), strconv.Itoa(stat.IV), strconv.Itoa(stat.EV))
+	}
+	stats.Render(os.Stdout, style)
 
-func commandPokedex(config *cmdConfig, args []string) error {
-	fmt.Println("Your Pokedex:")
+	fmt.Printf("Caught: %s in %s with a %s\n", r.CaughtAt.Format(time.DateOnly), r.CaughtIn, r.Ball)
 
-	for _, pokemon := range config.pokedex {
-		fmt.Println("  -", pokemon.Name)
+	if r.Sprite != "" {
+		fmt.Println("Sprite:", r.Sprite)
 	}
 
-	return nil
+	if r.Version != nil {
+		if r.Version.GameIndex != 0 {
+			fmt.Println("Game index:", r.Version.GameIndex)
+		}
+		fmt.Printf("Moves in %s:\n", r.Version.VersionGroup)
+		for _, move := range r.Version.Moves {
+			if move.Method == "level-up" {
+				fmt.Printf("  - %s (level %d)\n", move.Name, move.Level)
+			} else {
+				fmt.Printf("  - %s (%s)\n", move.Name, move.Method)
+			}
+		}
+	}
+}
+
+func getVersionDetails(config *cmdConfig, pokemon pokeapi.PokemonData) *versionDetails {
+	details := &versionDetails{
+		Version:      config.settings.Version,
+		VersionGroup: config.settings.VersionGroup,
+		Moves:        []learnedMove{},
+	}
+	for _, gameIndex := range pokemon.GameIndices {
+		if gameIndex.Version.Name == config.settings.Version {
+			details.GameIndex = gameIndex.GameIndex
+		}
+	}
+
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name != config.settings.VersionGroup {
+				continue
+			}
+			learned := learnedMove{Name: move.Move.Name, Method: detail.MoveLearnMethod.Name}
+			if learned.Method == "level-up" {
+				learned.Level = detail.LevelLearnedAt
+			}
+			details.Moves = append(details.Moves, learned)
+		}
+	}
+	return details
 }
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+// regionList is every region, and the one the trainer's in.
+type regionList struct {
+	Regions []string `json:"regions"`
+	Current string   `json:"current,omitempty"`
+}
+
+func (r regionList) printText(style table.Style) {
+	for _, region := range r.Regions {
+		fmt.Println(currentMarker(region, r.Current), region)
+	}
+}
+
+func commandRegions(config *cmdConfig, in cmdInput) error {
+	regions, err := pokeapi.GetRegions(config.cache)
+	if err != nil {
+		return err
+	}
+
+	r := regionList{Regions: []string{}, Current: config.region}
+	for _, region := range regions.Results {
+		r.Regions = append(r.Regions, region.Name)
+	}
+	return writeResult(config, r)
+}
+
+func commandRegion(config *cmdConfig, in cmdInput) error {
+	name := config.region
+	if in.isSet("region_name") {
+		name = in.get("region_name")
+	}
+	if name == "" {
+		return errors.New("you aren't in a region; use regions to list them")
+	}
+
+	region, err := pokeapi.GetRegion(name, config.cache)
+	if err != nil {
+		return lookupError(config, "region", name, err)
+	}
+
+	if region.Name != config.region {
+		config.moveTo(region.Name, "", "")
+	}
+
+	r := regionResult{Region: region.Name, Locations: resourceNames(region.Locations), Current: config.location}
+	if err := writeResult(config, r); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// regionResult is a region's locations, and the one the trainer's at.
+type regionResult struct {
+	Region    string   `json:"region"`
+	Locations []string `json:"locations"`
+	Current   string   `json:"current,omitempty"`
+}
+
+func (r regionResult) printText(style table.Style) {
+	fmt.Printf("Locations in %s:\n", r.Region)
+	for _, location := range r.Locations {
+		fmt.Println(currentMarker(location, r.Current), location)
+	}
+}
+
+func commandLocation(config *cmdConfig, in cmdInput) error {
+	name := config.location
+	if in.isSet("location_name") {
+		name = in.get("location_name")
+	}
+	if name == "" {
+		return errors.New("you aren't at a location; use travel to go to one")
+	}
+
+	location, err := pokeapi.GetLocation(name, config.cache)
+	if err != nil {
+		return lookupError(config, "location", name, err)
+	}
+
+	return writeResult(config, newLocationResult(config, location))
+}
+
+// locationResult is a location's areas, and the one the trainer's in.
+type locationResult struct {
+	Location string   `json:"location"`
+	Areas    []string `json:"areas"`
+	Current  string   `json:"current,omitempty"`
+}
+
+// newLocationResult lists location's areas.
+func newLocationResult(config *cmdConfig, location pokeapi.LocationData) locationResult {
+	return locationResult{Location: location.Name, Areas: resourceNames(location.Areas), Current: config.locationArea}
+}
+
+func (r locationResult) printText(style table.Style) {
+	fmt.Printf("Areas in %s:\n", r.Location)
+	for _, area := range r.Areas {
+		fmt.Println(currentMarker(area, r.Current), area)
+	}
+}
+
+func commandTravel(config *cmdConfig, in cmdInput) error {
+	name := in.get("location_name")
+
+	if config.region == "" {
+		return errors.New("you aren't in a region; use region to go to one")
+	}
+	if name == config.location {
+		return fmt.Errorf("you're already at %s", name)
+	}
+
+	region, err := pokeapi.GetRegion(config.region, config.cache)
+	if err != nil {
+		return err
+	}
+	if !containsResource(region.Locations, name) {
+		err := fmt.Errorf("%s isn't in %s", name, config.region)
+		return unknownName(err, name, func() []string { return resourceNames(region.Locations) })
+	}
+
+	location, err := pokeapi.GetLocation(name, config.cache)
+	if err != nil {
+		return lookupError(config, "location", name, err)
+	}
+
+	// no choice to make when there's only one area
+	area := ""
+	if len(location.Areas) == 1 {
+		area = location.Areas[0].Name
+	}
+	config.moveTo(config.region, location.Name, area)
+
+	if err := writeResult(config, travelResult{newLocationResult(config, location)}); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// travelResult is where the trainer traveled to.
+type travelResult struct {
+	locationResult
+}
+
+func (r travelResult) printText(style table.Style) {
+	fmt.Println("You traveled to", r.Location)
+	r.locationResult.printText(style)
+}
+
+func commandArea(config *cmdConfig, in cmdInput) error {
+	name := in.get("location_area")
+
+	if err := checkInCurrentLocation(config, name); err != nil {
+		return err
+	}
+
+	config.moveTo(config.region, config.location, name)
+
+	r := positionResult{Region: config.region, Location: config.location, LocationArea: name}
+	if err := writeResult(config, r); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// positionResult is where the trainer is now.
+type positionResult struct {
+	Region       string `json:"region"`
+	Location     string `json:"location"`
+	LocationArea string `json:"location_area"`
+}
+
+func (r positionResult) printText(style table.Style) {
+	fmt.Println("You are now in", r.LocationArea)
+}
+
+func commandTravelLog(config *cmdConfig, in cmdInput) error {
+	log := travelLog{TravelLog: config.travelLog}
+	if log.TravelLog == nil {
+		log.TravelLog = []travelLogEntry{}
+	}
+	return writeResult(config, log)
+}
+
+type travelLog struct {
+	TravelLog []travelLogEntry `json:"travel_log"`
+}
+
+func (r travelLog) printText(style table.Style) {
+	if len(r.TravelLog) == 0 {
+		fmt.Println("You haven't been anywhere yet")
+		return
+	}
+
+	fmt.Println("Travel log:")
+	for _, entry := range r.TravelLog {
+		fmt.Printf("  %s  %s\n", entry.At.Format(time.DateTime), entry.place())
+	}
+}
+
+type travelLogEntry struct {
+	At           time.Time `json:"at"`
+	Region       string    `json:"region"`
+	Location     string    `json:"location,omitempty"`
+	LocationArea string    `json:"location_area,omitempty"`
+}
+
+func (entry travelLogEntry) place() string {
+	place := entry.Region
+	for _, part := range []string{entry.Location, entry.LocationArea} {
+		if part != "" {
+			place += " / " + part
+		}
+	}
+	return place
+}
+
+// moveTo updates the trainer's position and records it in the travel log.
+func (config *cmdConfig) moveTo(region, location, locationArea string) {
+	config.region = region
+	config.location = location
+	config.locationArea = locationArea
+	config.wild = nil
+	config.battle = nil
+	config.areaShiny = nil
+	config.travelLog = append(config.travelLog, travelLogEntry{
+		At:           time.Now(),
+		Region:       region,
+		Location:     location,
+		LocationArea: locationArea,
+	})
+}
+
+// checkInCurrentLocation returns an error unless locationArea is one of the
+// areas of the trainer's current location.
+func checkInCurrentLocation(config *cmdConfig, locationArea string) error {
+	if config.location == "" {
+		return errors.New("you aren't at a location; use travel to go to one")
+	}
+
+	location, err := pokeapi.GetLocation(config.location, config.cache)
+	if err != nil {
+		return err
+	}
+	if !containsResource(location.Areas, locationArea) {
+		err := fmt.Errorf("%s isn't an area of %s", locationArea, config.location)
+		return unknownName(err, locationArea, func() []string { return resourceNames(location.Areas) })
+	}
+
+	return nil
+}
+
+func resourceNames(resources []pokeapi.NamedAPIResource) []string {
+	names := []string{}
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	return names
+}
+
+func containsResource(resources []pokeapi.NamedAPIResource, name string) bool {
+	for _, resource := range resources {
+		if resource.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// currentMarker flags the entry in a listing that matches the trainer's
+// current position.
+func currentMarker(name, current string) string {
+	if name == current {
+		return "*"
+	}
+	return "-"
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+	"github.com/chuckatc/pokedexcli/internal/term"
+	"github.com/chuckatc/pokedexcli/internal/yaml"
+)
+
+// the formats results can be written in, the first being the default
+var outputFormats = []string{"text", "json", "yaml"}
+
+// result is what a command found, which it prints as text for people, or
+// which is encoded as JSON or YAML for scripts.
+type result interface {
+	printText(style table.Style)
+}
+
+// writeResult writes a command's result in the output format, with each
+// result a JSON value or YAML document of its own.
+func writeResult(config *cmdConfig, r result) error {
+	switch config.output {
+	case "json":
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			return err
+		}
+		fmt.Print("---\n" + string(data))
+	default:
+		r.printText(textStyle())
+	}
+	return nil
+}
+
+// textOutput reports whether results are written for people, so commands
+// can show things, like sprites, that only make sense to them.
+func (config *cmdConfig) textOutput() bool {
+	return config.output == "" || config.output == "text"
+}
+
+// textStyle is how text results are laid out for the terminal: colored as
+// much as it can show, unless NO_COLOR is set, and fitted to its width.
+// When stdout isn't a terminal, they're written plain and as wide as they
+// need to be.
+func textStyle() table.Style {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return table.Style{}
+	}
+
+	style := table.Style{Color: ansi.DetectColorMode(os.Getenv)}
+	if width, _, err := term.Size(fd); err == nil {
+		style.Width = width
+	}
+	return style
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chuckatc/pokedexcli/internal/battle"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+)
+
+const (
+	maxIV       = 31
+	maxEV       = 252
+	maxTotalEVs = 510
+	maxLevel    = 100
+
+	// level of Pokemon caught without an encounter level to go by
+	defaultCatchLevel = 5
+
+	pokeBall = "poke-ball"
+
+	maxNicknameLength = 12
+)
+
+// OwnedPokemon is one particular Pokemon the trainer has caught.
+type OwnedPokemon struct {
+	ID         int           `json:"id"`
+	Species    string        `json:"species"`
+	Pokemon    string        `json:"pokemon"`
+	DexNumber  int           `json:"dex_number"`
+	Nickname   string        `json:"nickname,omitempty"`
+	Form       string        `json:"form,omitempty"`
+	Shiny      bool          `json:"shiny,omitempty"`
+	Level      int           `json:"level"`
+	Experience int           `json:"experience"`
+	GrowthRate string        `json:"growth_rate"`
+	IVs        battle.Stats  `json:"ivs"`
+	EVs        battle.Stats  `json:"evs"`
+	Nature     battle.Nature `json:"nature"`
+	BaseStats  battle.Stats  `json:"base_stats"`
+	Types      []string      `json:"types"`
+	CaughtAt   time.Time     `json:"caught_at"`
+	CaughtIn   string        `json:"caught_in"`
+	Ball       string        `json:"ball"`
+}
+
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// statField returns the field of stats holding the named stat, or nil for
+// stats that only matter in battle, like accuracy.
+func statField(stats *battle.Stats, name string) *int {
+	switch name {
+	case "hp":
+		return &stats.HP
+	case "attack":
+		return &stats.Attack
+	case "defense":
+		return &stats.Defense
+	case "special-attack":
+		return &stats.SpecialAttack
+	case "special-defense":
+		return &stats.SpecialDefense
+	case "speed":
+		return &stats.Speed
+	}
+	return nil
+}
+
+func statValue(stats battle.Stats, name string) int {
+	if field := statField(&stats, name); field != nil {
+		return *field
+	}
+	return 0
+}
+
+// DisplayName gives a Pokemon's nickname along with its species, or just the
+// species when it hasn't been nicknamed.
+func (p *OwnedPokemon) DisplayName() string {
+	if p.Nickname == "" {
+		return p.Pokemon
+	}
+	return fmt.Sprintf("%s (%s)", p.Nickname, p.Pokemon)
+}
+
+func (p *OwnedPokemon) Stats() battle.Stats {
+	return battle.CalcStats(p.BaseStats, p.IVs, p.EVs, p.Level, p.Nature)
+}
+
+// newOwnedPokemon rolls the individual values and nature for a Pokemon that
+// was just caught at level.
+func newOwnedPokemon(config *cmdConfig, pokemonData pokeapi.PokemonData, level int) (*OwnedPokemon, error) {
+	species, err := pokeapi.GetPokemonSpecies(pokemonData.Species.Name, config.cache)
+	if err != nil {
+		return nil, err
+	}
+	growthRate, err := pokeapi.GetGrowthRate(species.GrowthRate.Name, config.cache)
+	if err != nil {
+		return nil, err
+	}
+	nature, err := randomNature(config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.nextOwnedID++
+	p := OwnedPokemon{
+		ID:         config.nextOwnedID,
+		Species:    species.Name,
+		Pokemon:    pokemonData.Name,
+		DexNumber:  species.ID,
+		Level:      level,
+		Experience: experienceForLevel(growthRate, level),
+		GrowthRate: growthRate.Name,
+		IVs: battle.Stats{
+			HP:             config.rng.Intn(maxIV + 1),
+			Attack:         config.rng.Intn(maxIV + 1),
+			Defense:        config.rng.Intn(maxIV + 1),
+			SpecialAttack:  config.rng.Intn(maxIV + 1),
+			SpecialDefense: config.rng.Intn(maxIV + 1),
+			Speed:          config.rng.Intn(maxIV + 1),
+		},
+		Nature:    nature,
+		BaseStats: baseStats(pokemonData),
+		CaughtAt:  time.Now(),
+		CaughtIn:  config.locationArea,
+		Ball:      pokeBall,
+	}
+	for _, pokeType := range pokemonData.Types {
+		p.Types = append(p.Types, pokeType.Type.Name)
+	}
+	for _, variety := range species.Varieties {
+		if variety.Pokemon.Name == pokemonData.Name && !variety.IsDefault {
+			p.Form = strings.TrimPrefix(pokemonData.Name, species.Name+"-")
+		}
+	}
+
+	return &p, nil
+}
+
+// findVariety returns the name of the Pokemon for one of the species' forms,
+// given either its full name, like vulpix-alola, or just the form, like alola.
+func findVariety(config *cmdConfig, name, form string) (string, error) {
+	pokemonData, err := pokeapi.GetPokemonData(name, config.cache)
+	if err != nil {
+		return "", lookupError(config, "pokemon", name, err)
+	}
+	species, err := pokeapi.GetPokemonSpecies(pokemonData.Species.Name, config.cache)
+	if err != nil {
+		return "", err
+	}
+
+	forms := []string{}
+	for _, variety := range species.Varieties {
+		varietyName := variety.Pokemon.Name
+		if varietyName == form || varietyName == species.Name+"-"+form {
+			return varietyName, nil
+		}
+		forms = append(forms, varietyName)
+	}
+	return "", fmt.Errorf("%s has no form %s (it has: %s)", species.Name, form, strings.Join(forms, ", "))
+}
+
+func randomNature(config *cmdConfig) (battle.Nature, error) {
+	natures, err := pokeapi.GetNatures(config.cache)
+	if err != nil {
+		return battle.Nature{}, err
+	}
+	if len(natures.Results) == 0 {
+		return battle.Nature{}, nil
+	}
+
+	name := natures.Results[config.rng.Intn(len(natures.Results))].Name
+	nature, err := pokeapi.GetNature(name, config.cache)
+	if err != nil {
+		return battle.Nature{}, err
+	}
+
+	return battle.Nature{
+		Name:      nature.Name,
+		Increased: nature.IncreasedStat.Name,
+		Decreased: nature.DecreasedStat.Name,
+	}, nil
+}
+
+func experienceForLevel(growthRate pokeapi.GrowthRateData, level int) int {
+	for _, l := range growthRate.Levels {
+		if l.Level == level {
+			return l.Experience
+		}
+	}
+	return 0
+}
+
+// gainExperience adds experience to p, leveling it up as far as its growth
+// rate allows, and returns how many levels it gained.
+func gainExperience(config *cmdConfig, p *OwnedPokemon, experience int) (int, error) {
+	growthRate, err := pokeapi.GetGrowthRate(p.GrowthRate, config.cache)
+	if err != nil {
+		return 0, err
+	}
+
+	p.Experience += experience
+	levels := 0
+	for p.Level < maxLevel && p.Experience >= experienceForLevel(growthRate, p.Level+1) {
+		p.Level++
+		levels++
+	}
+	return levels, nil
+}
+
+// gainEffort adds the effort values a defeated Pokemon yields, within the
+// per-stat and total limits.
+func gainEffort(p *OwnedPokemon, defeated pokeapi.PokemonData) {
+	total := p.EVs.HP + p.EVs.Attack + p.EVs.Defense +
+		p.EVs.SpecialAttack + p.EVs.SpecialDefense + p.EVs.Speed
+
+	for _, stat := range defeated.Stats {
+		ev := statField(&p.EVs, stat.Stat.Name)
+		if ev == nil {
+			continue
+		}
+		gain := min(stat.Effort, maxEV-*ev, maxTotalEVs-total)
+		if gain > 0 {
+			*ev += gain
+			total += gain
+		}
+	}
+}
+
+// findOwned looks up one of the trainer's Pokemon by ID, or else the first
+// one with the given nickname or of the named species.
+func findOwned(config *cmdConfig, idOrName string) (*OwnedPokemon, error) {
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		for _, p := range allOwned(config) {
+			if p.ID == id {
+				return p, nil
+			}
+		}
+		return nil, fmt.Errorf("you don't have a Pokemon with ID %d", id)
+	}
+
+	for _, p := range allOwned(config) {
+		if strings.EqualFold(p.Pokemon, idOrName) || strings.EqualFold(p.Species, idOrName) ||
+			strings.EqualFold(p.Nickname, idOrName) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("you haven't caught %s yet", idOrName)
+}
+
+func commandRename(config *cmdConfig, in cmdInput) error {
+	p, err := findOwned(config, in.get("pokemon_id"))
+	if err != nil {
+		return err
+	}
+
+	nickname := in.get("nickname")
+	if err := setNickname(p, nickname); err != nil {
+		return err
+	}
+
+	if err := writeResult(config, ownedChange{Action: "renamed", Pokemon: summarize(p)}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+func setNickname(p *OwnedPokemon, nickname string) error {
+	nickname = strings.TrimSpace(nickname)
+	if utf8.RuneCountInString(nickname) > maxNicknameLength {
+		return fmt.Errorf("nicknames can be at most %d characters", maxNicknameLength)
+	}
+	p.Nickname = nickname
+	return nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+const (
+	maxPartySize = 6
+	boxSize      = 30
+)
+
+// ownedSummary is one of the trainer's Pokemon, as party and pc list it.
+type ownedSummary struct {
+	ID       int    `json:"id"`
+	Pokemon  string `json:"pokemon"`
+	Nickname string `json:"nickname,omitempty"`
+	Level    int    `json:"level"`
+
+	displayName string
+}
+
+func summarize(p *OwnedPokemon) ownedSummary {
+	return ownedSummary{
+		ID:          p.ID,
+		Pokemon:     p.Pokemon,
+		Nickname:    p.Nickname,
+		Level:       p.Level,
+		displayName: p.DisplayName(),
+	}
+}
+
+func summarizeOwned(pokemon []*OwnedPokemon) []ownedSummary {
+	summaries := []ownedSummary{}
+	for _, p := range pokemon {
+		summaries = append(summaries, summarize(p))
+	}
+	return summaries
+}
+
+// ownedChange is what was done with one of the trainer's Pokemon: it was
+// deposited, withdrawn, swapped with another, released or renamed.
+type ownedChange struct {
+	Action  string        `json:"action"`
+	Pokemon ownedSummary  `json:"pokemon"`
+	Other   *ownedSummary `json:"other,omitempty"`
+	Box     int           `json:"box,omitempty"`
+}
+
+func (r ownedChange) printText(style table.Style) {
+	switch r.Action {
+	case "deposited":
+		fmt.Printf("%s was sent to box %d\n", r.Pokemon.displayName, r.Box)
+	case "withdrew":
+		fmt.Printf("%s joined your party\n", r.Pokemon.displayName)
+	case "swapped":
+		fmt.Printf("Swapped %s and %s\n", r.Pokemon.displayName, r.Other.displayName)
+	case "released":
+		fmt.Printf("%s was released. Bye!\n", r.Pokemon.displayName)
+	case "renamed":
+		if r.Pokemon.Nickname == "" {
+			fmt.Printf("%s's nickname was removed\n", r.Pokemon.Pokemon)
+		} else {
+			fmt.Printf("%s is now called %s\n", r.Pokemon.Pokemon, r.Pokemon.Nickname)
+		}
+	}
+}
+
+type partyResult struct {
+	Party []ownedSummary `json:"party"`
+}
+
+func (r partyResult) printText(style table.Style) {
+	if len(r.Party) == 0 {
+		fmt.Println("Your party is empty")
+		return
+	}
+
+	fmt.Println("Your party:")
+	for i, p := range r.Party {
+		fmt.Printf("  %d. [%d] %s (Lv. %d)\n", i+1, p.ID, p.displayName, p.Level)
+	}
+}
+
+func commandParty(config *cmdConfig, in cmdInput) error {
+	return writeResult(config, partyResult{Party: summarizeOwned(config.party)})
+}
+
+type pcResult struct {
+	Boxes [][]ownedSummary `json:"boxes"`
+}
+
+func (r pcResult) printText(style table.Style) {
+	if len(r.Boxes) == 0 {
+		fmt.Println("Your PC boxes are empty")
+		return
+	}
+
+	for i, box := range r.Boxes {
+		fmt.Printf("Box %d (%d/%d):\n", i+1, len(box), boxSize)
+		for _, p := range box {
+			fmt.Printf("  - [%d] %s (Lv. %d)\n", p.ID, p.displayName, p.Level)
+		}
+	}
+}
+
+func commandPC(config *cmdConfig, in cmdInput) error {
+	r := pcResult{Boxes: [][]ownedSummary{}}
+	for _, box := range config.boxes {
+		r.Boxes = append(r.Boxes, summarizeOwned(box))
+	}
+	return writeResult(config, r)
+}
+
+func commandDeposit(config *cmdConfig, in cmdInput) error {
+	if config.battle != nil {
+		return errors.New("you're in a battle; use fight or run")
+	}
+	i, err := partyIndex(config, in.get("pokemon_id"))
+	if err != nil {
+		return err
+	}
+	if len(config.party) == 1 {
+		return errors.New("you can't deposit your last party Pokemon")
+	}
+
+	p := config.party[i]
+	config.party = slices.Delete(config.party, i, i+1)
+	box := store(config, p)
+	if err := writeResult(config, ownedChange{Action: "deposited", Pokemon: summarize(p), Box: box + 1}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+func commandWithdraw(config *cmdConfig, in cmdInput) error {
+	box, i, err := boxIndex(config, in.get("pokemon_id"))
+	if err != nil {
+		return err
+	}
+	if len(config.party) >= maxPartySize {
+		return errors.New("your party is full; deposit a Pokemon first")
+	}
+
+	p := config.boxes[box][i]
+	config.boxes[box] = slices.Delete(config.boxes[box], i, i+1)
+	config.party = append(config.party, p)
+	if err := writeResult(config, ownedChange{Action: "withdrew", Pokemon: summarize(p)}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+func commandSwap(config *cmdConfig, in cmdInput) error {
+	a, err := ownedSlot(config, in.get("pokemon_id"))
+	if err != nil {
+		return err
+	}
+	b, err := ownedSlot(config, in.get("other_pokemon_id"))
+	if err != nil {
+		return err
+	}
+
+	if config.battle != nil && (*a == config.battle.owned || *b == config.battle.owned) {
+		return fmt.Errorf("%s is in a battle; use fight or run", config.battle.owned.DisplayName())
+	}
+
+	*a, *b = *b, *a
+	other := summarize(*b)
+	if err := writeResult(config, ownedChange{Action: "swapped", Pokemon: summarize(*a), Other: &other}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+func commandRelease(config *cmdConfig, in cmdInput) error {
+	if config.battle != nil {
+		return errors.New("you're in a battle; use fight or run")
+	}
+	id := in.get("pokemon_id")
+
+	var p *OwnedPokemon
+	if i, err := partyIndex(config, id); err == nil {
+		if len(config.party) == 1 {
+			return errors.New("you can't release your last party Pokemon")
+		}
+		p = config.party[i]
+		config.party = slices.Delete(config.party, i, i+1)
+	} else if box, i, err := boxIndex(config, id); err == nil {
+		p = config.boxes[box][i]
+		config.boxes[box] = slices.Delete(config.boxes[box], i, i+1)
+	} else {
+		return err
+	}
+
+	if err := writeResult(config, ownedChange{Action: "released", Pokemon: summarize(p)}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+// addOwned puts a newly caught Pokemon in the party, or in the PC once the
+// party is full, returning the number of the box it went to, or 0 for the
+// party.
+func addOwned(config *cmdConfig, p *OwnedPokemon) int {
+	if len(config.party) < maxPartySize {
+		config.party = append(config.party, p)
+		return 0
+	}
+	return store(config, p) + 1
+}
+
+// store puts p in the first PC box with room, adding a box if they're all
+// full, and returns the box's index.
+func store(config *cmdConfig, p *OwnedPokemon) int {
+	for i, box := range config.boxes {
+		if len(box) < boxSize {
+			config.boxes[i] = append(box, p)
+			return i
+		}
+	}
+	config.boxes = append(config.boxes, []*OwnedPokemon{p})
+	return len(config.boxes) - 1
+}
+
+// allOwned returns every Pokemon the trainer has, party first.
+func allOwned(config *cmdConfig) []*OwnedPokemon {
+	owned := slices.Clone(config.party)
+	for _, box := range config.boxes {
+		owned = append(owned, box...)
+	}
+	return owned
+}
+
+func parseOwnedID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%s isn't a Pokemon ID", arg)
+	}
+	return id, nil
+}
+
+func partyIndex(config *cmdConfig, arg string) (int, error) {
+	id, err := parseOwnedID(arg)
+	if err != nil {
+		return 0, err
+	}
+	for i, p := range config.party {
+		if p.ID == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("there's no Pokemon with ID %d in your party", id)
+}
+
+func boxIndex(config *cmdConfig, arg string) (int, int, error) {
+	id, err := parseOwnedID(arg)
+	if err != nil {
+		return 0, 0, err
+	}
+	for box, pokemon := range config.boxes {
+		for i, p := range pokemon {
+			if p.ID == id {
+				return box, i, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("there's no Pokemon with ID %d in your PC", id)
+}
+
+// ownedSlot returns the party or box slot holding the Pokemon with the given
+// ID, so it can be swapped in place.
+func ownedSlot(config *cmdConfig, arg string) (**OwnedPokemon, error) {
+	if i, err := partyIndex(config, arg); err == nil {
+		return &config.party[i], nil
+	}
+	box, i, err := boxIndex(config, arg)
+	if err != nil {
+		return nil, fmt.Errorf("you don't have a Pokemon with ID %s", arg)
+	}
+	return &config.boxes[box][i], nil
+}
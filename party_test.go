@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestAddOwnedFillsPartyThenBoxes(t *testing.T) {
+	config := cmdConfig{}
+	for id := 1; id <= maxPartySize+boxSize+1; id++ {
+		addOwned(&config, &OwnedPokemon{ID: id, Pokemon: "pidgey"})
+	}
+
+	if len(config.party) != maxPartySize {
+		t.Errorf("party has %d Pokemon; want %d", len(config.party), maxPartySize)
+	}
+	if len(config.boxes) != 2 {
+		t.Fatalf("there are %d boxes; want 2", len(config.boxes))
+	}
+	if len(config.boxes[0]) != boxSize || len(config.boxes[1]) != 1 {
+		t.Errorf("boxes hold %d and %d Pokemon; want %d and 1",
+			len(config.boxes[0]), len(config.boxes[1]), boxSize)
+	}
+}
+
+func TestSwapBetweenPartyAndBox(t *testing.T) {
+	lead := &OwnedPokemon{ID: 1, Pokemon: "bulbasaur"}
+	other := &OwnedPokemon{ID: 2, Pokemon: "pidgey"}
+	boxed := &OwnedPokemon{ID: 3, Pokemon: "rattata"}
+	config := cmdConfig{
+		party: []*OwnedPokemon{lead, other},
+		boxes: [][]*OwnedPokemon{{boxed}},
+	}
+
+	if err := commandSwap(&config, parseArgs(t, "swap", "1", strconv.Itoa(boxed.ID))); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.party[0] != boxed || config.boxes[0][0] != lead {
+		t.Errorf("expected bulbasaur and rattata to trade places")
+	}
+	if config.party[1] != other {
+		t.Errorf("expected pidgey to stay in place")
+	}
+}
+
+func TestBattlingPokemonStaysInParty(t *testing.T) {
+	lead := &OwnedPokemon{ID: 1, Pokemon: "bulbasaur"}
+	other := &OwnedPokemon{ID: 2, Pokemon: "pidgey"}
+	boxed := &OwnedPokemon{ID: 3, Pokemon: "rattata"}
+	config := cmdConfig{
+		party:  []*OwnedPokemon{lead, other},
+		boxes:  [][]*OwnedPokemon{{boxed}},
+		battle: &activeBattle{owned: lead},
+	}
+
+	cases := []struct {
+		args    []string
+		allowed bool
+	}{
+		{args: []string{"release", "1"}},
+		{args: []string{"release", "3"}},
+		{args: []string{"deposit", "2"}},
+		{args: []string{"swap", "1", "3"}},
+		{args: []string{"swap", "3", "1"}},
+		{args: []string{"swap", "2", "3"}, allowed: true},
+	}
+
+	for _, c := range cases {
+		cmd := newRegistry()[c.args[0]]
+		err := cmd.callback(&config, parseArgs(t, c.args[0], c.args[1:]...))
+		if (err == nil) != c.allowed {
+			t.Errorf("%v in a battle returned %v", c.args, err)
+		}
+	}
+	if config.party[0] != lead || len(config.party) != 2 {
+		t.Errorf("the battling Pokemon left the party: %v", config.party)
+	}
+}
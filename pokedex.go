@@ -0,0 +1,315 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+// IDs above this belong to alternate forms rather than species
+const maxSpeciesID = 10000
+
+const nationalDex = "national"
+
+type dexEntry struct {
+	Name   string `json:"name"`
+	Seen   bool   `json:"seen"`
+	Caught bool   `json:"caught"`
+}
+
+// dexListing is one line of a Pokedex, national or regional.
+type dexListing struct {
+	number    int
+	dexNumber int
+	name      string
+}
+
+func commandPokedex(config *cmdConfig, in cmdInput) error {
+	if err := onlyOneOf(in, "seen", "caught", "missing"); err != nil {
+		return err
+	}
+	filter := seenOrCaught
+	switch {
+	case in.isSet("seen"):
+		filter = seenNotCaught
+	case in.isSet("caught"):
+		filter = caughtOnly
+	case in.isSet("missing"):
+		filter = notCaught
+	}
+	dexName := in.get("pokedex_name")
+	showStats := in.isSet("stats")
+
+	listings, err := getDexListings(config, dexName)
+	if err != nil {
+		return err
+	}
+
+	// caught species show what the trainer has named them
+	nicknames := map[int][]string{}
+	for _, p := range allOwned(config) {
+		if p.Nickname != "" {
+			nicknames[p.DexNumber] = append(nicknames[p.DexNumber], p.Nickname)
+		}
+	}
+
+	r := pokedexResult{Pokedex: dexName, Entries: []pokedexEntry{}, Total: len(listings)}
+	for _, listing := range listings {
+		entry := config.pokedex[listing.dexNumber]
+		if entry.Seen {
+			r.Seen++
+		}
+		if entry.Caught {
+			r.Caught++
+		}
+		if !matchesDexFilter(entry, filter) {
+			continue
+		}
+		r.Entries = append(r.Entries, pokedexEntry{
+			Number:    listing.number,
+			Name:      listing.name,
+			Status:    dexStatus(entry),
+			Nicknames: nicknames[listing.dexNumber],
+		})
+	}
+
+	if showStats {
+		if r.Stats, err = getDexStats(config); err != nil {
+			return err
+		}
+	}
+
+	return writeResult(config, r)
+}
+
+// pokedexResult is a Pokedex's listing, and how much of it the trainer has
+// seen and caught.
+type pokedexResult struct {
+	Pokedex string         `json:"pokedex"`
+	Entries []pokedexEntry `json:"entries"`
+	Seen    int            `json:"seen"`
+	Caught  int            `json:"caught"`
+	Total   int            `json:"total"`
+	Stats   *dexStats      `json:"stats,omitempty"`
+}
+
+type pokedexEntry struct {
+	Number    int      `json:"number"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	Nicknames []string `json:"nicknames,omitempty"`
+}
+
+// dexStats is how much of each generation has been caught, and of each of
+// the current region's Pokedexes.
+type dexStats struct {
+	Generations []dexProgress `json:"generations"`
+	Region      string        `json:"region,omitempty"`
+	Pokedexes   []dexProgress `json:"pokedexes,omitempty"`
+}
+
+type dexProgress struct {
+	Name   string `json:"name"`
+	Caught int    `json:"caught"`
+	Total  int    `json:"total"`
+}
+
+func (r pokedexResult) printText(style table.Style) {
+	fmt.Printf("Your Pokedex (%s):\n", r.Pokedex)
+	entries := table.New(
+		table.Column{Align: table.Right},
+		table.Column{CanShrink: true},
+		table.Column{},
+		table.Column{CanShrink: true},
+	)
+	entries.Indent = 2
+	for _, entry := range r.Entries {
+		entries.AddRow(fmt.Sprintf("#%03d", entry.Number), entry.Name, entry.Status, strings.Join(entry.Nicknames, ", "))
+	}
+	entries.Render(os.Stdout, style)
+	fmt.Printf("Seen %d, caught %d of %d (%s)\n", r.Seen, r.Caught, r.Total, percent(r.Caught, r.Total))
+
+	if r.Stats == nil {
+		return
+	}
+	fmt.Println("By generation:")
+	printDexProgress(r.Stats.Generations, style)
+	if r.Stats.Region != "" {
+		fmt.Printf("In %s:\n", r.Stats.Region)
+		printDexProgress(r.Stats.Pokedexes, style)
+	}
+}
+
+func printDexProgress(progress []dexProgress, style table.Style) {
+	t := table.New(
+		table.Column{CanShrink: true},
+		table.Column{Align: table.Right},
+		table.Column{},
+		table.Column{Align: table.Right},
+	)
+	t.Indent = 2
+	for _, p := range progress {
+		t.AddRow(p.Name, fmt.Sprintf("%d/%d", p.Caught, p.Total), table.Bar(p.Caught, p.Total, 20), percent(p.Caught, p.Total))
+	}
+	t.Render(os.Stdout, style)
+}
+
+// dexFilter is which entries a Pokedex listing shows.
+type dexFilter int
+
+const (
+	seenOrCaught dexFilter = iota
+	seenNotCaught
+	caughtOnly
+	notCaught
+)
+
+func matchesDexFilter(entry dexEntry, filter dexFilter) bool {
+	switch filter {
+	case seenNotCaught:
+		return entry.Seen && !entry.Caught
+	case caughtOnly:
+		return entry.Caught
+	case notCaught:
+		return !entry.Caught
+	default:
+		return entry.Seen || entry.Caught
+	}
+}
+
+func dexStatus(entry dexEntry) string {
+	switch {
+	case entry.Caught:
+		return "caught"
+	case entry.Seen:
+		return "seen"
+	default:
+		return "-"
+	}
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+func getDexListings(config *cmdConfig, dexName string) ([]dexListing, error) {
+	listings := []dexListing{}
+
+	if dexName == nationalDex {
+		species, err := pokeapi.GetPokemonSpeciesList(config.cache)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range species.Results {
+			id := pokeapi.IDFromURL(s.URL)
+			listings = append(listings, dexListing{number: id, dexNumber: id, name: s.Name})
+		}
+		slices.SortFunc(listings, func(a, b dexListing) int { return cmp.Compare(a.number, b.number) })
+		return listings, nil
+	}
+
+	pokedex, err := pokeapi.GetPokedex(dexName, config.cache)
+	if err != nil {
+		return nil, lookupError(config, "pokedex", dexName, err)
+	}
+	for _, entry := range pokedex.PokemonEntries {
+		listings = append(listings, dexListing{
+			number:    entry.EntryNumber,
+			dexNumber: pokeapi.IDFromURL(entry.PokemonSpecies.URL),
+			name:      entry.PokemonSpecies.Name,
+		})
+	}
+	return listings, nil
+}
+
+func getDexStats(config *cmdConfig) (*dexStats, error) {
+	generations, err := pokeapi.GetGenerations(config.cache)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dexStats{Generations: []dexProgress{}}
+	for _, g := range generations.Results {
+		generation, err := pokeapi.GetGeneration(g.Name, config.cache)
+		if err != nil {
+			return nil, err
+		}
+		caught := 0
+		for _, species := range generation.PokemonSpecies {
+			if config.pokedex[pokeapi.IDFromURL(species.URL)].Caught {
+				caught++
+			}
+		}
+		stats.Generations = append(stats.Generations, dexProgress{
+			Name:   generation.Name,
+			Caught: caught,
+			Total:  len(generation.PokemonSpecies),
+		})
+	}
+
+	if config.region == "" {
+		return stats, nil
+	}
+
+	region, err := pokeapi.GetRegion(config.region, config.cache)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Region = region.Name
+	for _, p := range region.Pokedexes {
+		listings, err := getDexListings(config, p.Name)
+		if err != nil {
+			return nil, err
+		}
+		caught := 0
+		for _, listing := range listings {
+			if config.pokedex[listing.dexNumber].Caught {
+				caught++
+			}
+		}
+		stats.Pokedexes = append(stats.Pokedexes, dexProgress{Name: p.Name, Caught: caught, Total: len(listings)})
+	}
+
+	return stats, nil
+}
+
+// markSeen records a Pokemon in the Pokedex from its name and resource URL,
+// looking up the species of alternate forms.
+func markSeen(config *cmdConfig, name, url string) {
+	dexNumber := pokeapi.IDFromURL(url)
+	if dexNumber > maxSpeciesID {
+		pokemonData, err := pokeapi.GetPokemonData(name, config.cache)
+		if err != nil {
+			return
+		}
+		name = pokemonData.Species.Name
+		dexNumber = pokeapi.IDFromURL(pokemonData.Species.URL)
+	}
+	if dexNumber == 0 {
+		return
+	}
+
+	entry := config.pokedex[dexNumber]
+	if entry.Name == "" {
+		entry.Name = name
+	}
+	entry.Seen = true
+	config.pokedex[dexNumber] = entry
+}
+
+func markCaught(config *cmdConfig, p *OwnedPokemon) {
+	config.pokedex[p.DexNumber] = dexEntry{
+		Name:   p.Species,
+		Seen:   true,
+		Caught: true,
+	}
+}
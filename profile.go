@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// the profile made on first run, and for saves from before profiles
+const defaultProfile = "trainer"
+
+const maxTrainerNameLength = 12
+
+// trainers start out with 3000 Pokedollars, as in the games
+const startingMoney = 3000
+
+// trainer IDs are 16 bits, shown as five digits
+const maxTrainerID = 65535
+
+func newTrainer(config *cmdConfig, name string) trainer {
+	return trainer{
+		Name:      name,
+		ID:        config.rng.Intn(maxTrainerID + 1),
+		StartedAt: time.Now(),
+		Money:     startingMoney,
+		Badges:    []string{},
+	}
+}
+
+// startSession loads the active profile. The first time, it makes one from
+// the save kept before there were profiles, if there is one.
+func startSession(config *cmdConfig) error {
+	if config.dataDir == "" {
+		config.load(saveData{Trainer: newTrainer(config, defaultProfile)})
+		return nil
+	}
+
+	gc, err := loadGlobalConfig(config.dataDir)
+	if err != nil {
+		return err
+	}
+	config.aliases, config.macros = gc.Aliases, gc.Macros
+
+	name := gc.ActiveProfile
+	path := profilePath(config.dataDir, name)
+	if name == "" {
+		name = defaultProfile
+		path = legacySavePath(config.dataDir)
+	}
+
+	data, err := loadSave(path)
+	if err != nil {
+		return err
+	}
+	if data.Trainer.Name == "" {
+		data.Trainer = newTrainer(config, name)
+	}
+	config.load(data)
+
+	if err := config.save(); err != nil {
+		return err
+	}
+	return config.saveGlobal()
+}
+
+func commandProfile(config *cmdConfig, in cmdInput) error {
+	if config.dataDir == "" {
+		return errors.New("there's nowhere to keep profiles on this system")
+	}
+
+	action := in.get("action")
+	if action == "list" {
+		if in.isSet("trainer_name") {
+			return in.usageError("profile list doesn't take a name")
+		}
+		return listProfiles(config)
+	}
+	if !in.isSet("trainer_name") {
+		return in.usageError("profile %s needs a <trainer_name>", action)
+	}
+
+	name := in.get("trainer_name")
+	if err := checkTrainerName(name); err != nil {
+		return err
+	}
+	switch action {
+	case "new":
+		return newProfile(config, name)
+	case "switch":
+		return switchProfile(config, name)
+	default:
+		return deleteProfile(config, name)
+	}
+}
+
+func listProfiles(config *cmdConfig) error {
+	// the active profile is saved first, so its play time is current
+	if err := config.save(); err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(profilePath(config.dataDir, "*"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Trainers:")
+	for _, path := range paths {
+		data, err := loadSave(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		t := data.Trainer
+
+		fmt.Printf("%s %s (ID %05d)\n", currentMarker(t.Name, config.trainer.Name), t.Name, t.ID)
+		fmt.Printf("    started %s, played %s\n", t.StartedAt.Format(time.DateOnly), formatPlayTime(t.PlayTime))
+		fmt.Printf("    money: ₽%d, badges: %d, caught: %d\n", t.Money, len(t.Badges), countCaught(data.Pokedex))
+		for _, item := range slices.Sorted(maps.Keys(data.Inventory)) {
+			fmt.Printf("    %s x%d\n", item, data.Inventory[item])
+		}
+	}
+
+	return nil
+}
+
+func newProfile(config *cmdConfig, name string) error {
+	if profileExists(config, name) {
+		return fmt.Errorf("there's already a trainer called %s", name)
+	}
+
+	if err := config.save(); err != nil {
+		return err
+	}
+	config.load(saveData{Trainer: newTrainer(config, name)})
+	if err := config.save(); err != nil {
+		return err
+	}
+	if err := config.saveGlobal(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Welcome, %s! Your trainer ID is %05d\n", name, config.trainer.ID)
+	return nil
+}
+
+func switchProfile(config *cmdConfig, name string) error {
+	if name == config.trainer.Name {
+		return fmt.Errorf("you're already playing as %s", name)
+	}
+	if !profileExists(config, name) {
+		return fmt.Errorf("there's no trainer called %s", name)
+	}
+
+	data, err := loadSave(profilePath(config.dataDir, name))
+	if err != nil {
+		return err
+	}
+	if err := config.save(); err != nil {
+		return err
+	}
+	config.load(data)
+	if err := config.saveGlobal(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Welcome back, %s!\n", name)
+	return nil
+}
+
+func deleteProfile(config *cmdConfig, name string) error {
+	if name == config.trainer.Name {
+		return errors.New("you can't delete the trainer you're playing as; switch to another first")
+	}
+	if !profileExists(config, name) {
+		return fmt.Errorf("there's no trainer called %s", name)
+	}
+
+	answer, ok := prompt(config, fmt.Sprintf("Delete %s and all their Pokemon? (y/n) ", name))
+	if !ok || strings.ToLower(answer) != "y" {
+		fmt.Println("Kept", name)
+		return nil
+	}
+
+	if err := os.Remove(profilePath(config.dataDir, name)); err != nil {
+		return err
+	}
+	fmt.Println("Deleted", name)
+	return nil
+}
+
+func checkTrainerName(name string) error {
+	if name == "" || len(name) > maxTrainerNameLength {
+		return fmt.Errorf("trainer names are 1 to %d characters", maxTrainerNameLength)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return errors.New("trainer names are letters, digits, - and _")
+		}
+	}
+	return nil
+}
+
+func profileExists(config *cmdConfig, name string) bool {
+	_, err := os.Stat(profilePath(config.dataDir, name))
+	return err == nil
+}
+
+func formatPlayTime(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func countCaught(pokedex map[int]dexEntry) int {
+	caught := 0
+	for _, entry := range pokedex {
+		if entry.Caught {
+			caught++
+		}
+	}
+	return caught
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProfilesKeepSeparateSaves(t *testing.T) {
+	config := cmdConfig{
+		dataDir: t.TempDir(),
+		rng:     rand.New(rand.NewSource(1)),
+	}
+	if err := startSession(&config); err != nil {
+		t.Fatal(err)
+	}
+	if config.trainer.Name != defaultProfile {
+		t.Fatalf("started as %q; want %q", config.trainer.Name, defaultProfile)
+	}
+	addOwned(&config, &OwnedPokemon{ID: 1, Pokemon: "pikachu", Species: "pikachu", DexNumber: 25})
+	config.moveTo("kanto", "pallet-town", "pallet-town-area")
+
+	if err := commandProfile(&config, parseArgs(t, "profile", "new", "Blue")); err != nil {
+		t.Fatal(err)
+	}
+	if config.trainer.Name != "blue" || len(config.party) != 0 || len(config.pokedex) != 0 {
+		t.Fatalf("new profile is %q with %d Pokemon", config.trainer.Name, len(config.party))
+	}
+	if config.location != "" || len(config.travelLog) != 0 {
+		t.Errorf("new profile starts at %q with %d places traveled", config.location, len(config.travelLog))
+	}
+	if config.trainer.Money != startingMoney {
+		t.Errorf("new trainer has ₽%d; want ₽%d", config.trainer.Money, startingMoney)
+	}
+
+	if err := commandProfile(&config, parseArgs(t, "profile", "switch", defaultProfile)); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.party) != 1 || config.party[0].Pokemon != "pikachu" {
+		t.Errorf("switching back lost the party: %v", config.party)
+	}
+	if config.locationArea != "pallet-town-area" || len(config.travelLog) != 1 {
+		t.Errorf("switching back is at %q with %d places traveled; want pallet-town-area with 1",
+			config.locationArea, len(config.travelLog))
+	}
+
+	// a new session picks up the last active profile
+	restarted := cmdConfig{dataDir: config.dataDir, rng: config.rng}
+	if err := startSession(&restarted); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.trainer.Name != defaultProfile || restarted.trainer.ID != config.trainer.ID {
+		t.Errorf("restarted as %q (ID %d); want %q (ID %d)",
+			restarted.trainer.Name, restarted.trainer.ID, defaultProfile, config.trainer.ID)
+	}
+	if restarted.region != "kanto" || restarted.location != "pallet-town" {
+		t.Errorf("restarted in %q at %q; want kanto at pallet-town", restarted.region, restarted.location)
+	}
+
+	if err := commandProfile(&config, parseArgs(t, "profile", "delete", defaultProfile)); err == nil {
+		t.Error("deleted the active profile")
+	}
+	config.input = scannerInput{bufio.NewScanner(strings.NewReader("y\n"))}
+	if err := commandProfile(&config, parseArgs(t, "profile", "delete", "blue")); err != nil {
+		t.Fatal(err)
+	}
+	if profileExists(&config, "blue") {
+		t.Error("blue is still there after deleting it")
+	}
+}
+
+func TestLegacySaveBecomesProfile(t *testing.T) {
+	dataDir := t.TempDir()
+	legacy := `{"party": [{"id": 1, "pokemon": "eevee", "species": "eevee", "dex_number": 133}], "next_owned_id": 2}`
+	if err := os.WriteFile(legacySavePath(dataDir), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := cmdConfig{dataDir: dataDir, rng: rand.New(rand.NewSource(1))}
+	if err := startSession(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.trainer.Name != defaultProfile || len(config.party) != 1 {
+		t.Fatalf("migrated to %q with %d Pokemon", config.trainer.Name, len(config.party))
+	}
+	if !config.pokedex[133].Caught {
+		t.Error("eevee isn't caught in the Pokedex")
+	}
+	if !profileExists(&config, defaultProfile) {
+		t.Error("the migrated profile wasn't saved")
+	}
+}
+
+func TestCheckTrainerName(t *testing.T) {
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "red", valid: true},
+		{name: "ash_k-2", valid: true},
+		{name: "", valid: false},
+		{name: "../save", valid: false},
+		{name: "a-very-long-name", valid: false},
+	}
+
+	for _, c := range cases {
+		if err := checkTrainerName(c.name); (err == nil) != c.valid {
+			t.Errorf("checkTrainerName(%q) = %v", c.name, err)
+		}
+	}
+}
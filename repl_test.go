@@ -5,44 +5,101 @@ import "testing"
 func TestCleanInput(t *testing.T) {
 	cases := []struct {
 		input    string
-		expected []string
+		expected [][]string
 	}{
 		{
 			input:    "  hello  world  ",
-			expected: []string{"hello", "world"},
+			expected: [][]string{{"hello", "world"}},
 		},
 		{
 			input:    "HELLO WORLD ",
-			expected: []string{"hello", "world"},
+			expected: [][]string{{"hello", "WORLD"}},
 		},
 		{
 			input:    " heLLo World",
-			expected: []string{"hello", "world"},
+			expected: [][]string{{"hello", "World"}},
 		},
 		{
 			input:    " Hello ",
-			expected: []string{"hello"},
+			expected: [][]string{{"hello"}},
+		},
+		{
+			input:    `rename 3 "Mr Sparky"`,
+			expected: [][]string{{"rename", "3", "Mr Sparky"}},
+		},
+		{
+			input:    "RENAME pikachu 'Bolt'",
+			expected: [][]string{{"rename", "pikachu", "Bolt"}},
+		},
+		{
+			input:    `rename 3 ""`,
+			expected: [][]string{{"rename", "3", ""}},
+		},
+		{
+			input:    `rename 3 Mr\ Mime`,
+			expected: [][]string{{"rename", "3", "Mr Mime"}},
+		},
+		{
+			input:    `rename 3 "Say \"Hi\" \o/" 'C:\'`,
+			expected: [][]string{{"rename", "3", `Say "Hi" \o/`, `C:\`}},
+		},
+		{
+			input:    "catch vulpix --form=alola",
+			expected: [][]string{{"catch", "vulpix", "--form", "alola"}},
+		},
+		{
+			input:    `rename 3 --name="Jo=Jo" x=y`,
+			expected: [][]string{{"rename", "3", "--name", "Jo=Jo", "x=y"}},
+		},
+		{
+			input:    "catch vulpix --form=",
+			expected: [][]string{{"catch", "vulpix", "--form", ""}},
+		},
+		{
+			input:    "map; MAP;;explore 'a;b' ; ",
+			expected: [][]string{{"map"}, {"map"}, {"explore", "a;b"}},
+		},
+		{
+			input:    "  ",
+			expected: [][]string{},
 		},
 	}
 
 	for _, c := range cases {
-		actual := cleanInput(c.input)
-		// Check the length of the actual slice against the expected slice
-		// if they don't match, use t.Errorf to print an error message
-		// and fail the test
+		actual, err := cleanInput(c.input)
+		if err != nil {
+			t.Errorf("cleaning %q: %v", c.input, err)
+			continue
+		}
+		// Check the number of commands, then the words of each
 		if len(actual) != len(c.expected) {
-			t.Errorf("length of slice is %d; want %d", len(actual), len(c.expected))
+			t.Errorf("got %d commands from %q; want %d", len(actual), c.input, len(c.expected))
+			continue
 		}
 
 		for i := range actual {
-			word := actual[i]
-			expectedWord := c.expected[i]
-			// Check each word in the slice
-			// if they don't match, use t.Errorf to print an error message
-			// and fail the test
-			if word != expectedWord {
-				t.Errorf("words not matching at position %d: got %s, expected %s", i, word, expectedWord)
+			if len(actual[i]) != len(c.expected[i]) {
+				t.Errorf("length of slice is %d; want %d", len(actual[i]), len(c.expected[i]))
+				continue
+			}
+			for j := range actual[i] {
+				word := actual[i][j]
+				expectedWord := c.expected[i][j]
+				// Check each word in the slice
+				// if they don't match, use t.Errorf to print an error message
+				// and fail the test
+				if word != expectedWord {
+					t.Errorf("words not matching at position %d: got %s, expected %s", j, word, expectedWord)
+				}
 			}
 		}
 	}
 }
+
+func TestCleanInputErrors(t *testing.T) {
+	for _, input := range []string{`rename 3 "Sparky`, "rename 3 'Sparky", `catch pikachu\`} {
+		if words, err := cleanInput(input); err == nil {
+			t.Errorf("cleaning %q gave %q; want an error", input, words)
+		}
+	}
+}
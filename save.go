@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// the odds of a Pokemon being shiny in recent games, 1 in 4096
+const defaultShinyOdds = 4096
+
+type settings struct {
+	Version      string `json:"version,omitempty"`
+	VersionGroup string `json:"version_group,omitempty"`
+	ShinyOdds    int    `json:"shiny_odds,omitempty"`
+
+	// "auto" or unset to detect the terminal's protocol
+	SpriteProtocol string `json:"sprite_protocol,omitempty"`
+
+	// only use downloaded sprites and cries
+	Offline bool `json:"offline,omitempty"`
+
+	// offer to run what misspelled commands and names probably meant
+	Autocorrect bool `json:"autocorrect,omitempty"`
+}
+
+func (s settings) shinyOdds() int {
+	if s.ShinyOdds <= 0 {
+		return defaultShinyOdds
+	}
+	return s.ShinyOdds
+}
+
+// trainer is who a profile belongs to.
+type trainer struct {
+	Name      string        `json:"name"`
+	ID        int           `json:"id"`
+	StartedAt time.Time     `json:"started_at"`
+	PlayTime  time.Duration `json:"play_time"`
+	Money     int           `json:"money"`
+	Badges    []string      `json:"badges"`
+}
+
+// saveData is everything in a trainer profile.
+type saveData struct {
+	Trainer     trainer           `json:"trainer"`
+	Settings    settings          `json:"settings"`
+	Party       []*OwnedPokemon   `json:"party"`
+	Boxes       [][]*OwnedPokemon `json:"boxes"`
+	NextOwnedID int               `json:"next_owned_id"`
+	Pokedex     map[int]dexEntry  `json:"pokedex"`
+	Inventory   map[string]int    `json:"inventory"`
+
+	// where the trainer is, and everywhere they've been
+	Region       string           `json:"region,omitempty"`
+	Location     string           `json:"location,omitempty"`
+	LocationArea string           `json:"location_area,omitempty"`
+	TravelLog    []travelLogEntry `json:"travel_log,omitempty"`
+}
+
+// globalConfig holds what's shared by every profile.
+type globalConfig struct {
+	ActiveProfile string            `json:"active_profile"`
+	Aliases       map[string]string `json:"aliases,omitempty"`
+	Macros        map[string]string `json:"macros,omitempty"`
+}
+
+// defaultDataDir returns where profiles live, or "" if there's no config
+// directory to keep them in.
+func defaultDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli")
+}
+
+func profilePath(dataDir, name string) string {
+	return filepath.Join(dataDir, "profiles", name+".json")
+}
+
+// legacySavePath is where the save lived before there were profiles.
+func legacySavePath(dataDir string) string {
+	return filepath.Join(dataDir, "save.json")
+}
+
+func globalConfigPath(dataDir string) string {
+	return filepath.Join(dataDir, "config.json")
+}
+
+func loadSave(path string) (saveData, error) {
+	var data saveData
+	if err := readJSON(path, &data); err != nil {
+		return saveData{}, err
+	}
+	return data, nil
+}
+
+func loadGlobalConfig(dataDir string) (globalConfig, error) {
+	var gc globalConfig
+	if err := readJSON(globalConfigPath(dataDir), &gc); err != nil {
+		return globalConfig{}, err
+	}
+	return gc, nil
+}
+
+// readJSON decodes the file at path into v, leaving v alone if there's no
+// such file.
+func readJSON(path string, v any) error {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contents, v)
+}
+
+// writeJSON replaces the file at path only once the new one is fully
+// written.
+func writeJSON(path string, v any) error {
+	contents, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, contents, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// load makes data the active profile, starting a fresh session for it.
+func (config *cmdConfig) load(data saveData) {
+	*config = cmdConfig{
+		cache:            config.cache,
+		cmdRegistry:      config.cmdRegistry,
+		dataDir:          config.dataDir,
+		aliases:          config.aliases,
+		macros:           config.macros,
+		rng:              config.rng,
+		input:            config.input,
+		output:           config.output,
+		history:          config.history,
+		detectedProtocol: config.detectedProtocol,
+		assets:           config.assets,
+
+		trainer:     data.Trainer,
+		settings:    data.Settings,
+		party:       data.Party,
+		boxes:       data.Boxes,
+		nextOwnedID: data.NextOwnedID,
+		pokedex:     data.Pokedex,
+		inventory:   data.Inventory,
+		playStart:   time.Now(),
+
+		region:       data.Region,
+		location:     data.Location,
+		locationArea: data.LocationArea,
+		travelLog:    data.TravelLog,
+	}
+
+	if config.inventory == nil {
+		config.inventory = map[string]int{}
+	}
+	if config.pokedex == nil {
+		config.pokedex = map[int]dexEntry{}
+	}
+	// saves from before the Pokedex tracked anything still count what
+	// they've caught
+	for _, p := range allOwned(config) {
+		markCaught(config, p)
+	}
+}
+
+// save writes the active profile, adding the time played since the last
+// save.
+func (config *cmdConfig) save() error {
+	if config.dataDir == "" || config.trainer.Name == "" {
+		return nil
+	}
+
+	now := time.Now()
+	if !config.playStart.IsZero() {
+		config.trainer.PlayTime += now.Sub(config.playStart)
+	}
+	config.playStart = now
+
+	return writeJSON(profilePath(config.dataDir, config.trainer.Name), saveData{
+		Trainer:     config.trainer,
+		Settings:    config.settings,
+		Party:       config.party,
+		Boxes:       config.boxes,
+		NextOwnedID: config.nextOwnedID,
+		Pokedex:     config.pokedex,
+		Inventory:   config.inventory,
+
+		Region:       config.region,
+		Location:     config.location,
+		LocationArea: config.locationArea,
+		TravelLog:    config.travelLog,
+	})
+}
+
+// saveGlobal writes what's shared by every profile, with the active profile
+// being the trainer's.
+func (config *cmdConfig) saveGlobal() error {
+	if config.dataDir == "" {
+		return nil
+	}
+	return writeJSON(globalConfigPath(config.dataDir), globalConfig{
+		ActiveProfile: config.trainer.Name,
+		Aliases:       config.aliases,
+		Macros:        config.macros,
+	})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runScript runs the commands read from r a line at a time, as typed at the
+// prompt but with no one there to answer questions. Blank lines and lines
+// starting with # are skipped. It stops at the first command that fails,
+// returning the exit status: 0 if everything succeeded, 1 if not.
+func runScript(config *cmdConfig, r io.Reader, name string) int {
+	// questions like a nickname for a catch go unanswered
+	config.input = nil
+
+	status := 0
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !runScriptLine(config, line) {
+			fmt.Fprintf(os.Stderr, "stopped at %s, line %d\n", name, lineNum)
+			status = 1
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "can't read %s: %s\n", name, err)
+		status = 1
+	}
+
+	if err := config.save(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		status = 1
+	}
+	return status
+}
+
+func runScriptLine(config *cmdConfig, line string) bool {
+	commands, err := cleanInput(line)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	for _, words := range commands {
+		if !runCommand(config, words) {
+			return false
+		}
+	}
+	return true
+}
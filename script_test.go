@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	ran := []string{}
+	record := func(config *cmdConfig, in cmdInput) error {
+		ran = append(ran, in.get("what"))
+		if in.get("what") == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	cases := []struct {
+		script         string
+		expectedRan    []string
+		expectedStatus int
+	}{
+		{script: "do a\n\n# a comment\ndo b; do c\n", expectedRan: []string{"a", "b", "c"}, expectedStatus: 0},
+		{script: "do a; do fail; do b\ndo c\n", expectedRan: []string{"a", "fail"}, expectedStatus: 1},
+		{script: "do a\ndo 'b\ndo c\n", expectedRan: []string{"a"}, expectedStatus: 1},
+		{script: "do a\nnope\ndo c\n", expectedRan: []string{"a"}, expectedStatus: 1},
+		{script: "", expectedRan: []string{}, expectedStatus: 0},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			ran = []string{}
+			config := cmdConfig{
+				cmdRegistry: map[string]cliCommand{"do": {
+					name:     "do",
+					callback: record,
+					args:     []argSpec{{name: "what"}},
+				}},
+			}
+
+			status := runScript(&config, strings.NewReader(c.script), "test.pdx")
+
+			if status != c.expectedStatus {
+				t.Errorf("expected status %d, got %d", c.expectedStatus, status)
+			}
+			if !slices.Equal(ran, c.expectedRan) {
+				t.Errorf("expected %q to run, got %q", c.expectedRan, ran)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/chuckatc/pokedexcli/internal/sprite"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+// the settings that can be changed, in the order they're listed
+var settingNames = []string{"version", "shiny-odds", "sprite-protocol", "offline", "autocorrect"}
+
+func commandSettings(config *cmdConfig, in cmdInput) error {
+	if !in.isSet("setting") {
+		r := settingsResult{
+			Version:        config.settings.Version,
+			ShinyOdds:      config.settings.shinyOdds(),
+			SpriteProtocol: config.settings.SpriteProtocol,
+			Offline:        config.settings.Offline,
+			Autocorrect:    config.settings.Autocorrect,
+		}
+		if r.Version == "" {
+			r.Version = "all"
+		}
+		if r.SpriteProtocol == "" {
+			r.SpriteProtocol = "auto"
+		}
+		return writeResult(config, r)
+	}
+	name := in.get("setting")
+	if !in.isSet("value") {
+		return in.usageError("settings %s needs a <value>", name)
+	}
+
+	value := in.get("value")
+	change := settingChange{Setting: name, Value: value}
+	switch name {
+	case "version":
+		return setVersion(config, value)
+	case "shiny-odds":
+		odds, err := strconv.Atoi(value)
+		if err != nil || odds < 1 {
+			return errors.New("shiny-odds takes a whole number n, for 1 in n odds")
+		}
+		config.settings.ShinyOdds = odds
+		change.message = fmt.Sprintf("Pokemon are now shiny 1 time in %d", odds)
+	case "sprite-protocol":
+		if _, ok := sprite.ParseProtocol(value); !ok && value != "auto" {
+			return errors.New("sprite-protocol is one of auto, blocks, kitty, iterm2 or sixel")
+		}
+		config.settings.SpriteProtocol = value
+		change.message = "Sprites are now drawn with " + value
+	case "offline":
+		switch value {
+		case "on":
+			config.settings.Offline = true
+			change.message = "Sprites now only come from downloaded assets"
+		case "off":
+			config.settings.Offline = false
+			change.message = "Sprites are now fetched when they aren't downloaded"
+		default:
+			return errors.New("offline is on or off")
+		}
+	case "autocorrect":
+		switch value {
+		case "on":
+			config.settings.Autocorrect = true
+			change.message = "You'll be asked whether to use suggestions for misspellings"
+		case "off":
+			config.settings.Autocorrect = false
+			change.message = "Misspellings now only get suggestions"
+		default:
+			return errors.New("autocorrect is on or off")
+		}
+	}
+
+	if err := writeResult(config, change); err != nil {
+		return err
+	}
+	return config.save()
+}
+
+// settingsResult is every setting, as settings lists them.
+type settingsResult struct {
+	Version        string `json:"version"`
+	ShinyOdds      int    `json:"shiny_odds"`
+	SpriteProtocol string `json:"sprite_protocol"`
+	Offline        bool   `json:"offline"`
+	Autocorrect    bool   `json:"autocorrect"`
+}
+
+func (r settingsResult) printText(style table.Style) {
+	fmt.Println("version:", r.Version)
+	fmt.Printf("shiny-odds: 1/%d\n", r.ShinyOdds)
+	fmt.Println("sprite-protocol:", r.SpriteProtocol)
+	fmt.Println("offline:", onOff(r.Offline))
+	fmt.Println("autocorrect:", onOff(r.Autocorrect))
+}
+
+// settingChange is a setting's new value, and what it means for the
+// trainer.
+type settingChange struct {
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+
+	message string
+}
+
+func (r settingChange) printText(style table.Style) {
+	fmt.Println(r.message)
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
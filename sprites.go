@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chuckatc/pokedexcli/internal/ansi"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/sprite"
+	"github.com/chuckatc/pokedexcli/internal/term"
+)
+
+// how long to wait for terminals that don't answer device attribute queries
+const da1Timeout = 200 * time.Millisecond
+
+// the version whose sprites stand for each generation
+var generationVersions = map[int]string{
+	1: "red",
+	2: "crystal",
+	3: "emerald",
+	4: "platinum",
+	5: "black",
+	6: "x",
+	7: "ultra-sun",
+}
+
+func commandSprite(config *cmdConfig, in cmdInput) error {
+	name := in.get("pokemon_name")
+	shiny, back := in.isSet("shiny"), in.isSet("back")
+
+	version := config.settings.Version
+	if in.isSet("gen") {
+		gen := in.getInt("gen")
+		var ok bool
+		if version, ok = generationVersions[gen]; !ok {
+			return fmt.Errorf("there are no sprites for generation %d", gen)
+		}
+	}
+
+	pokemonData, err := pokeapi.GetPokemonData(name, config.cache)
+	if err != nil {
+		return lookupError(config, "pokemon", name, err)
+	}
+
+	return showSprite(config, pokemonData.SpriteURL(version, shiny, back))
+}
+
+func showSprite(config *cmdConfig, url string) error {
+	if url == "" {
+		return errors.New("there's no sprite for that")
+	}
+
+	data, err := loadAsset(config, url)
+	if err != nil {
+		return err
+	}
+
+	return sprite.Draw(os.Stdout, data, spriteProtocol(config), ansi.DetectColorMode(os.Getenv))
+}
+
+// spriteProtocol returns the protocol from the trainer's settings, or else
+// the one the terminal supports, asking it only the first time.
+func spriteProtocol(config *cmdConfig) sprite.Protocol {
+	if protocol, ok := sprite.ParseProtocol(config.settings.SpriteProtocol); ok {
+		return protocol
+	}
+	if config.detectedProtocol == nil {
+		protocol := sprite.DetectProtocol(os.Getenv, queryDA1)
+		config.detectedProtocol = &protocol
+	}
+	return *config.detectedProtocol
+}
+
+func queryDA1() (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return "", errors.New("not a terminal")
+	}
+	return term.Query(os.Stdin, os.Stdout, sprite.DA1, 'c', da1Timeout)
+}
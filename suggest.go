@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/chuckatc/pokedexcli/internal/fuzzy"
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/pokecache"
+)
+
+// how many close matches to suggest for a misspelling
+const maxSuggestions = 3
+
+// the PokeAPI lists of names that misspellings are matched against
+var nameLists = map[string]func(*pokecache.Cache) (pokeapi.NamedAPIResourceList, error){
+	"pokemon":         pokeapi.GetPokemonList,
+	"pokemon-species": pokeapi.GetPokemonSpeciesList,
+	"pokedex":         pokeapi.GetPokedexList,
+	"region":          pokeapi.GetRegions,
+	"location":        pokeapi.GetLocationList,
+	"location-area":   pokeapi.GetLocationAreaList,
+	"move":            pokeapi.GetMoveList,
+	"item":            pokeapi.GetItemList,
+}
+
+// unknownNameError is for a name that isn't any of the names that would
+// fit, which the REPL follows up with the closest of those.
+type unknownNameError struct {
+	err        error
+	name       string
+	candidates func() []string
+}
+
+func (e *unknownNameError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unknownNameError) Unwrap() error {
+	return e.err
+}
+
+// unknownName returns err as the error for name, which isn't one of the
+// candidates.
+func unknownName(err error, name string, candidates func() []string) error {
+	return &unknownNameError{err: err, name: name, candidates: candidates}
+}
+
+// lookupError explains why looking up name, one of the PokeAPI's list of
+// resource names, failed with err. Names the PokeAPI doesn't have get
+// suggestions.
+func lookupError(config *cmdConfig, resource, name string, err error) error {
+	what := name
+	if resource != "pokemon" {
+		what = strings.ReplaceAll(resource, "-", " ") + " " + name
+	}
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return fmt.Errorf("can't look up %s: %w", what, err)
+	}
+	notFound := fmt.Errorf("can't find %s", what)
+	return unknownName(notFound, name, func() []string {
+		return apiNames(config, resource)
+	})
+}
+
+// apiNames returns every name in one of the PokeAPI's lists, fetching it
+// once a session.
+func apiNames(config *cmdConfig, resource string) []string {
+	if names, ok := config.nameLists[resource]; ok {
+		return names
+	}
+
+	list, err := nameLists[resource](config.cache)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, result := range list.Results {
+		names = append(names, result.Name)
+	}
+
+	if config.nameLists == nil {
+		config.nameLists = map[string][]string{}
+	}
+	config.nameLists[resource] = names
+	return names
+}
+
+// runCommand runs the command words spell out, or the commands it stands
+// for when it's an alias or macro, suggesting what might have been meant
+// when the command or a name in it is misspelled. With autocorrect on, the
+// trainer can run the suggestion instead. It reports whether the commands
+// all succeeded, stopping at the first that doesn't.
+func runCommand(config *cmdConfig, words []string) bool {
+	commands, err := expandCommand(config, words)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	for _, words := range commands {
+		if !runExpanded(config, words) {
+			return false
+		}
+	}
+	return true
+}
+
+// runExpanded runs a command that isn't an alias or macro.
+func runExpanded(config *cmdConfig, words []string) bool {
+	name, args := words[0], words[1:]
+
+	cliCmd, ok := config.cmdRegistry[name]
+	if !ok {
+		suggestions := fuzzy.Closest(name, commandNames(config), maxSuggestions)
+		if corrected, ok := suggest(config, errors.New("Unknown command"), suggestions); ok {
+			return runCommand(config, append([]string{corrected}, args...))
+		}
+		return false
+	}
+
+	err := callCommand(config, cliCmd, args)
+	var unknown *unknownNameError
+	if errors.As(err, &unknown) {
+		suggestions := fuzzy.Closest(unknown.name, unknown.candidates(), maxSuggestions)
+		corrected, ok := suggest(config, err, suggestions)
+		if !ok {
+			return false
+		}
+		args = slices.Clone(args)
+		for i, arg := range args {
+			if strings.EqualFold(arg, unknown.name) {
+				args[i] = corrected
+			}
+		}
+		err = callCommand(config, cliCmd, args)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	return true
+}
+
+// callCommand parses args by what the command declares, and calls it with
+// them if they make sense.
+func callCommand(config *cmdConfig, cliCmd cliCommand, args []string) error {
+	in, err := parseInput(cliCmd, args)
+	if err != nil {
+		return err
+	}
+	if in.isSet("output") {
+		defer func(output string) { config.output = output }(config.output)
+		config.output = in.get("output")
+	}
+	if cliCmd.textOnly && !config.textOutput() {
+		return fmt.Errorf("%s only writes text, so it can't be used with -output %s", cliCmd.name, config.output)
+	}
+	return cliCmd.callback(config, in)
+}
+
+// suggest prints err with the suggestions for what might have been meant,
+// and with autocorrect on, asks whether to use the first one instead.
+func suggest(config *cmdConfig, err error, suggestions []string) (string, bool) {
+	if len(suggestions) == 0 {
+		fmt.Fprintln(os.Stderr, err)
+		return "", false
+	}
+	fmt.Fprintf(os.Stderr, "%s; did you mean %s?\n", err, orList(suggestions))
+
+	if !config.settings.Autocorrect {
+		return "", false
+	}
+	answer, ok := prompt(config, fmt.Sprintf("Use %s? (y/n) ", suggestions[0]))
+	if !ok || strings.ToLower(answer) != "y" {
+		return "", false
+	}
+	return suggestions[0], true
+}
+
+// orList joins words like "a, b or c".
+func orList(words []string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+)
+
+func TestRunCommandAutocorrects(t *testing.T) {
+	calls := [][]string{}
+	catch := func(config *cmdConfig, in cmdInput) error {
+		name := in.get("pokemon_name")
+		calls = append(calls, []string{name})
+		if name != "pikachu" {
+			return unknownName(errors.New("there's no "+name+" here"), name, func() []string {
+				return []string{"pichu", "pikachu", "raichu"}
+			})
+		}
+		return nil
+	}
+
+	cases := []struct {
+		line        string
+		autocorrect bool
+		answers     string
+		expected    [][]string
+	}{
+		{line: "catch pikachu", expected: [][]string{{"pikachu"}}},
+		{line: "catch pikachi", expected: [][]string{{"pikachi"}}},
+		{line: "catch pikachi", autocorrect: true, answers: "y\n", expected: [][]string{{"pikachi"}, {"pikachu"}}},
+		{line: "catch pikachi", autocorrect: true, answers: "n\n", expected: [][]string{{"pikachi"}}},
+		{line: "cacth pikachu", expected: [][]string{}},
+		{line: "cacth pikachu", autocorrect: true, answers: "y\n", expected: [][]string{{"pikachu"}}},
+		{line: "cacth pikachi", autocorrect: true, answers: "y\ny\n", expected: [][]string{{"pikachi"}, {"pikachu"}}},
+	}
+
+	for _, c := range cases {
+		calls = [][]string{}
+		config := cmdConfig{
+			cmdRegistry: map[string]cliCommand{"catch": {
+				name:     "catch",
+				callback: catch,
+				args:     []argSpec{{name: "pokemon_name", kind: nameArg}},
+			}},
+			settings: settings{Autocorrect: c.autocorrect},
+			input:    scannerInput{bufio.NewScanner(strings.NewReader(c.answers))},
+		}
+
+		runCommand(&config, strings.Fields(c.line))
+
+		if !slices.EqualFunc(calls, c.expected, slices.Equal) {
+			t.Errorf("running %q (autocorrect %v, answering %q) called catch with %q; want %q",
+				c.line, c.autocorrect, c.answers, calls, c.expected)
+		}
+	}
+}
+
+func TestLookupError(t *testing.T) {
+	config := cmdConfig{nameLists: map[string][]string{
+		"location-area": {"canalave-city-area", "eterna-city-area"},
+	}}
+
+	err := lookupError(&config, "location-area", "eterna-cty-area", pokeapi.ErrNotFound)
+	if err.Error() != "can't find location area eterna-cty-area" {
+		t.Errorf("got error %q", err)
+	}
+	var unknown *unknownNameError
+	if !errors.As(err, &unknown) || !slices.Contains(unknown.candidates(), "eterna-city-area") {
+		t.Errorf("a missing area gets no suggestions from the location areas: %v", err)
+	}
+
+	// names can't be suggested when the PokeAPI couldn't be asked
+	offline := errors.New("offline")
+	err = lookupError(&config, "location-area", "eterna-cty-area", offline)
+	if errors.As(err, &unknown) {
+		t.Errorf("a failed lookup got suggestions: %v", err)
+	}
+	if !errors.Is(err, offline) {
+		t.Errorf("a failed lookup lost why it failed: %v", err)
+	}
+}
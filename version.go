@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+func commandVersion(config *cmdConfig, in cmdInput) error {
+	if in.isSet("version_name") {
+		return setVersion(config, in.get("version_name"))
+	}
+
+	versions, err := pokeapi.GetVersions(config.cache)
+	if err != nil {
+		return err
+	}
+	return writeResult(config, versionList{Versions: resourceNames(versions.Results), Current: config.settings.Version})
+}
+
+// versionList is every game version, and the one data is shown from, if
+// it isn't from all of them.
+type versionList struct {
+	Versions []string `json:"versions"`
+	Current  string   `json:"current,omitempty"`
+}
+
+func (r versionList) printText(style table.Style) {
+	if r.Current == "" {
+		fmt.Println("Showing data from all game versions")
+	}
+	for _, version := range r.Versions {
+		fmt.Println(currentMarker(version, r.Current), version)
+	}
+}
+
+// versionChoice is the game version data is now shown from, or none for
+// all of them.
+type versionChoice struct {
+	Version      string `json:"version,omitempty"`
+	VersionGroup string `json:"version_group,omitempty"`
+}
+
+func (r versionChoice) printText(style table.Style) {
+	if r.Version == "" {
+		fmt.Println("Showing data from all game versions")
+	} else {
+		fmt.Println("Showing data from", r.Version)
+	}
+}
+
+// setVersion shows data from the named game version, or from all of them.
+func setVersion(config *cmdConfig, name string) error {
+	if name == "all" {
+		config.settings.Version = ""
+		config.settings.VersionGroup = ""
+		if err := writeResult(config, versionChoice{}); err != nil {
+			return err
+		}
+		return config.save()
+	}
+
+	versions, err := pokeapi.GetVersions(config.cache)
+	if err != nil {
+		return err
+	}
+	if !containsResource(versions.Results, name) {
+		return fmt.Errorf("%s isn't a game version; use version to list them", name)
+	}
+
+	version, err := pokeapi.GetVersion(name, config.cache)
+	if err != nil {
+		return err
+	}
+
+	config.settings.Version = version.Name
+	config.settings.VersionGroup = version.VersionGroup.Name
+	if err := writeResult(config, versionChoice{Version: version.Name, VersionGroup: version.VersionGroup.Name}); err != nil {
+		return err
+	}
+
+	return config.save()
+}
+
+// hasVersion reports whether an encounter applies to the selected game
+// version; with no version selected, every encounter does.
+func hasVersion(config *cmdConfig, pokeEncounter pokeapi.PokemonEncounter) bool {
+	if config.settings.Version == "" {
+		return true
+	}
+	for _, versionDetail := range pokeEncounter.VersionDetails {
+		if versionDetail.Version.Name == config.settings.Version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/chuckatc/pokedexcli/internal/pokeapi"
+	"github.com/chuckatc/pokedexcli/internal/table"
+)
+
+// encounterSummary combines all of a location area's encounter details for
+// one method.
+type encounterSummary struct {
+	Method   string `json:"method"`
+	MinLevel int    `json:"min_level"`
+	MaxLevel int    `json:"max_level"`
+	Chance   int    `json:"chance"`
+}
+
+// whereResult is where a Pokemon can be found in the wild, in the selected
+// game version or in every one.
+type whereResult struct {
+	Pokemon  string              `json:"pokemon"`
+	Version  string              `json:"version,omitempty"`
+	Versions []versionEncounters `json:"versions"`
+}
+
+type versionEncounters struct {
+	Version string           `json:"version"`
+	Areas   []areaEncounters `json:"areas"`
+}
+
+type areaEncounters struct {
+	Area    string             `json:"area"`
+	Methods []encounterSummary `json:"methods"`
+}
+
+func (r whereResult) printText(style table.Style) {
+	if len(r.Versions) == 0 {
+		if r.Version == "" {
+			fmt.Printf("%s can't be found in the wild\n", r.Pokemon)
+		} else {
+			fmt.Printf("%s can't be found in the wild in %s\n", r.Pokemon, r.Version)
+		}
+		return
+	}
+
+	fmt.Printf("%s can be found in:\n", r.Pokemon)
+	for _, version := range r.Versions {
+		fmt.Printf("%s:\n", version.Version)
+		for _, area := range version.Areas {
+			fmt.Printf("  %s\n", area.Area)
+			for _, summary := range area.Methods {
+				fmt.Printf("    - %s, %s, %d%% chance\n",
+					summary.Method, levelRange(summary.MinLevel, summary.MaxLevel), summary.Chance)
+			}
+		}
+	}
+}
+
+func commandWhere(config *cmdConfig, in cmdInput) error {
+	name := in.get("pokemon_name")
+	allVersions := in.isSet("all") || config.settings.Version == ""
+
+	pokemonData, err := pokeapi.GetPokemonData(name, config.cache)
+	if err != nil {
+		return lookupError(config, "pokemon", name, err)
+	}
+
+	encounters, err := pokeapi.GetPokemonEncounters(pokemonData.LocationAreaEncounters, config.cache)
+	if err != nil {
+		return err
+	}
+
+	// version -> location area -> summaries by method
+	byVersion := map[string]map[string][]encounterSummary{}
+	for _, encounter := range encounters {
+		area := encounter.LocationArea.Name
+		for _, versionDetail := range encounter.VersionDetails {
+			version := versionDetail.Version.Name
+			if !allVersions && version != config.settings.Version {
+				continue
+			}
+			if byVersion[version] == nil {
+				byVersion[version] = map[string][]encounterSummary{}
+			}
+			for _, detail := range versionDetail.EncounterDetails {
+				byVersion[version][area] = addToSummaries(byVersion[version][area], encounterSummary{
+					Method:   detail.Method.Name,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+					Chance:   detail.Chance,
+				})
+			}
+		}
+	}
+
+	r := whereResult{Pokemon: name, Versions: []versionEncounters{}}
+	if !allVersions {
+		r.Version = config.settings.Version
+	}
+	for _, version := range slices.Sorted(maps.Keys(byVersion)) {
+		areas := byVersion[version]
+		v := versionEncounters{Version: version, Areas: []areaEncounters{}}
+		for _, area := range slices.Sorted(maps.Keys(areas)) {
+			v.Areas = append(v.Areas, areaEncounters{Area: area, Methods: areas[area]})
+		}
+		r.Versions = append(r.Versions, v)
+	}
+
+	return writeResult(config, r)
+}
+
+func addToSummaries(summaries []encounterSummary, detail encounterSummary) []encounterSummary {
+	for i, summary := range summaries {
+		if summary.Method != detail.Method {
+			continue
+		}
+		summaries[i].MinLevel = min(summary.MinLevel, detail.MinLevel)
+		summaries[i].MaxLevel = max(summary.MaxLevel, detail.MaxLevel)
+		summaries[i].Chance += detail.Chance
+		return summaries
+	}
+	return append(summaries, detail)
+}
+
+func levelRange(minLevel, maxLevel int) string {
+	if minLevel == maxLevel {
+		return fmt.Sprintf("level %d", minLevel)
+	}
+	return fmt.Sprintf("levels %d-%d", minLevel, maxLevel)
+}